@@ -3,10 +3,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/rumenvasilev/rvsecret/cmd/scan"
+	"github.com/rumenvasilev/rvsecret/cmd/signatures"
 	"github.com/rumenvasilev/rvsecret/internal/config"
 	"github.com/rumenvasilev/rvsecret/version"
 
@@ -36,19 +38,24 @@ var (
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
+		// errors may carry a specific exit code, e.g. when findings are over the failure threshold
+		var e interface{ ExitCode() int }
+		if errors.As(err, &e) {
+			os.Exit(e.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(scan.ScanCmd)
+	rootCmd.AddCommand(signatures.SignaturesCmd)
 	rootCmd.PersistentFlags().Bool("debug", false, "Print available debugging information to stdout")
 	viper.BindPFlag("global.debug", rootCmd.PersistentFlags().Lookup("debug")) //nolint:errcheck
 	rootCmd.PersistentFlags().String("config-file", config.DefaultConfig.Global.ConfigFile, "Config file location")
 	viper.BindPFlag("global.config-file", rootCmd.PersistentFlags().Lookup("config-file")) //nolint:errcheck
-	rootCmd.PersistentFlags().String(signaturesFile, config.DefaultConfig.Signatures.File, "file(s) containing detection signatures.")
+	rootCmd.PersistentFlags().StringSlice(signaturesFile, config.DefaultConfig.Signatures.File, "file(s) or directories containing additional detection signatures, they take precedence over the ones in signatures-path")
 	viper.BindPFlag("signatures.file", rootCmd.PersistentFlags().Lookup(signaturesFile)) //nolint:errcheck
-	rootCmd.PersistentFlags().String(signaturesPath, config.DefaultConfig.Signatures.Path, "path containing detection signatures.")
-	rootCmd.MarkFlagsMutuallyExclusive(signaturesFile, signaturesPath)
+	rootCmd.PersistentFlags().String(signaturesPath, config.DefaultConfig.Signatures.Path, "path containing detection signatures, all yaml and gitleaks toml files under it are loaded")
 	viper.BindPFlag("signatures.path", rootCmd.PersistentFlags().Lookup(signaturesPath)) //nolint:errcheck
 }
@@ -0,0 +1,34 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package scan
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanAzureDevOpsCmd represents the scanAzureDevOps command
+var scanAzureDevOpsCmd = &cobra.Command{
+	Use:   "azure-devops",
+	Short: "Scan the Git repositories of one or more Azure DevOps organizations or projects for secrets",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.AzureDevOps)
+		if err != nil {
+			return err
+		}
+		return scan.New(cfg).Run()
+	},
+}
+
+func init() {
+	ScanCmd.AddCommand(scanAzureDevOpsCmd)
+	scanAzureDevOpsCmd.Flags().StringP("api-token", "t", "", "Personal access token for Azure DevOps, with the Code (Read) and Project and Team (Read) scopes")
+	viper.BindPFlag("azure-devops.api-token", scanAzureDevOpsCmd.Flags().Lookup("api-token")) //nolint:errcheck
+	scanAzureDevOpsCmd.Flags().String("azure-devops-url", "", "Address of an Azure DevOps Server, e.g. https://tfs.example.com/tfs, https://dev.azure.com when empty")
+	viper.BindPFlag("azure-devops.url", scanAzureDevOpsCmd.Flags().Lookup("azure-devops-url")) //nolint:errcheck
+	scanAzureDevOpsCmd.Flags().StringSlice("organizations", nil, "List of Azure DevOps organizations, or organization/project, to scan")
+	viper.BindPFlag("azure-devops.organizations", scanAzureDevOpsCmd.Flags().Lookup("organizations")) //nolint:errcheck
+}
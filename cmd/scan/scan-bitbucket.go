@@ -0,0 +1,39 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package scan
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanBitbucketCmd represents the scanBitbucket command
+var scanBitbucketCmd = &cobra.Command{
+	Use:     "bitbucket",
+	Aliases: []string{"bb"},
+	Short:   "Scan one or more Bitbucket workspaces, projects or users for secrets",
+	Long: "Scan the repositories of Bitbucket Cloud workspaces, or of Bitbucket Server and Data Center projects (by key) " +
+		"and users. Set --bitbucket-url for Bitbucket Server and Data Center.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.Bitbucket)
+		if err != nil {
+			return err
+		}
+		return scan.New(cfg).Run()
+	},
+}
+
+func init() {
+	ScanCmd.AddCommand(scanBitbucketCmd)
+	scanBitbucketCmd.Flags().StringP("api-token", "t", "", "Access token, or app password or personal access token with --username")
+	viper.BindPFlag("bitbucket.api-token", scanBitbucketCmd.Flags().Lookup("api-token")) //nolint:errcheck
+	scanBitbucketCmd.Flags().String("username", "", "Username the app password or personal access token belongs to")
+	viper.BindPFlag("bitbucket.username", scanBitbucketCmd.Flags().Lookup("username")) //nolint:errcheck
+	scanBitbucketCmd.Flags().String("bitbucket-url", "", "Address of a Bitbucket Server or Data Center instance, e.g. https://bitbucket.example.com (default https://bitbucket.org)")
+	viper.BindPFlag("bitbucket.url", scanBitbucketCmd.Flags().Lookup("bitbucket-url")) //nolint:errcheck
+	scanBitbucketCmd.Flags().StringSlice("workspaces", nil, "List of Bitbucket Cloud workspaces, or Bitbucket Server projects and users to scan")
+	viper.BindPFlag("bitbucket.workspaces", scanBitbucketCmd.Flags().Lookup("workspaces")) //nolint:errcheck
+}
@@ -0,0 +1,35 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package scan
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanGitRemotesCmd represents the scanGitRemotes command
+var scanGitRemotesCmd = &cobra.Command{
+	Use:   "git-remotes <url>...",
+	Short: "Clone and scan git repositories from their URLs, on any host",
+	Long: "Clone and scan git repositories from their https or ssh URLs, without enumerating them through an API. " +
+		"The credentials for cloning over https are set per host in the remotes.credentials list of the config file.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.GitRemotes)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Remotes.URLs = args
+		}
+		return scan.New(cfg).Run()
+	},
+}
+
+func init() {
+	ScanCmd.AddCommand(scanGitRemotesCmd)
+	scanGitRemotesCmd.Flags().StringP("file", "f", "", "File with the clone URLs to scan, one per line")
+	viper.BindPFlag("remotes.file", scanGitRemotesCmd.Flags().Lookup("file")) //nolint:errcheck
+}
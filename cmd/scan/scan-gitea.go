@@ -0,0 +1,34 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package scan
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanGiteaCmd represents the scanGitea command
+var scanGiteaCmd = &cobra.Command{
+	Use:   "gitea",
+	Short: "Scan one or more Gitea or Forgejo organizations or users for secrets",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.Gitea)
+		if err != nil {
+			return err
+		}
+		return scan.New(cfg).Run()
+	},
+}
+
+func init() {
+	ScanCmd.AddCommand(scanGiteaCmd)
+	scanGiteaCmd.Flags().StringP("api-token", "t", "", "API token for access to Gitea, with read access to organizations, users and repositories")
+	viper.BindPFlag("gitea.api-token", scanGiteaCmd.Flags().Lookup("api-token")) //nolint:errcheck
+	scanGiteaCmd.Flags().String("gitea-url", "", "Address of the Gitea or Forgejo instance, e.g. https://gitea.example.com")
+	viper.BindPFlag("gitea.url", scanGiteaCmd.Flags().Lookup("gitea-url")) //nolint:errcheck
+	scanGiteaCmd.Flags().StringSlice("orgs", nil, "List of Gitea organizations or users to scan")
+	viper.BindPFlag("gitea.orgs", scanGiteaCmd.Flags().Lookup("orgs")) //nolint:errcheck
+}
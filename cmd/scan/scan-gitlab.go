@@ -28,6 +28,8 @@ func init() {
 	ScanCmd.AddCommand(scanGitlabCmd)
 	scanGitlabCmd.Flags().StringP("api-token", "t", "", "API token for access to gitlab, see doc for necessary scope")
 	viper.BindPFlag("gitlab.api-token", scanGitlabCmd.Flags().Lookup("api-token")) //nolint:errcheck
+	scanGitlabCmd.Flags().String("gitlab-url", "", "Address of a self-hosted GitLab instance, e.g. https://gitlab.example.com (default https://gitlab.com)")
+	viper.BindPFlag("gitlab.url", scanGitlabCmd.Flags().Lookup("gitlab-url")) //nolint:errcheck
 	scanGitlabCmd.Flags().StringSlice("projects", config.DefaultConfig.Gitlab.Targets, "List of Gitlab projects or users to scan")
 	viper.BindPFlag("gitlab.projects", scanGitlabCmd.Flags().Lookup("projects")) //nolint:errcheck
 }
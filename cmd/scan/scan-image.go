@@ -0,0 +1,32 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package scan
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/spf13/cobra"
+)
+
+// scanImageCmd represents the scanImage command
+var scanImageCmd = &cobra.Command{
+	Use:   "image <path>...",
+	Short: "Scan container images saved with docker save, or OCI image layouts",
+	Long: "Scan the files of every layer of container images, and the env vars and build history of their config. " +
+		"The paths are docker save tarballs, OCI image layout directories or tarballs of them.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.Image)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Local.Images = args
+		}
+		return scan.New(cfg).Run()
+	},
+}
+
+func init() {
+	ScanCmd.AddCommand(scanImageCmd)
+}
@@ -28,4 +28,12 @@ func init() {
 	ScanCmd.AddCommand(scanLocalPathCmd)
 	scanLocalPathCmd.Flags().StringSliceP("paths", "p", nil, "List of local paths to scan")
 	viper.BindPFlag("local.paths", scanLocalPathCmd.Flags().Lookup("paths")) //nolint:errcheck
+	scanLocalPathCmd.Flags().StringSlice("include", nil, "Only scan the files matching these glob patterns, relative to the scanned directories, e.g. **/*.yaml")
+	viper.BindPFlag("local.include", scanLocalPathCmd.Flags().Lookup("include")) //nolint:errcheck
+	scanLocalPathCmd.Flags().StringSlice("exclude", nil, "Skip the files and directories matching these glob patterns, relative to the scanned directories, e.g. **/testdata/**")
+	viper.BindPFlag("local.exclude", scanLocalPathCmd.Flags().Lookup("exclude")) //nolint:errcheck
+	scanLocalPathCmd.Flags().Bool("respect-gitignore", false, "Skip the files ignored by the .gitignore files found in the scanned directories")
+	viper.BindPFlag("local.respect-gitignore", scanLocalPathCmd.Flags().Lookup("respect-gitignore")) //nolint:errcheck
+	scanLocalPathCmd.Flags().Int("timeout", 0, "Stop the scan after this many seconds, 0 for no limit")
+	viper.BindPFlag("local.timeout", scanLocalPathCmd.Flags().Lookup("timeout")) //nolint:errcheck
 }
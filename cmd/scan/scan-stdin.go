@@ -0,0 +1,34 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package scan
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/stdin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanStdinCmd represents the scanStdin command
+var scanStdinCmd = &cobra.Command{
+	Use:   "stdin",
+	Short: "Scan the data piped to standard input",
+	Long: "Scan the data piped to standard input as the content of a single file, e.g. CI logs or the output of kubectl. " +
+		"The name of the file selects the signatures that match on file names and extensions. It exits with code 2 on any " +
+		"finding, or only on the findings meeting --fail-on-severity and --fail-on-tags when they are set.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.Stdin)
+		if err != nil {
+			return err
+		}
+		return scan.New(cfg).Run()
+	},
+}
+
+func init() {
+	ScanCmd.AddCommand(scanStdinCmd)
+	scanStdinCmd.Flags().String("name", stdin.DefaultName, "Name of the file the data is reported as")
+	viper.BindPFlag("local.stdin-name", scanStdinCmd.Flags().Lookup("name")) //nolint:errcheck
+}
@@ -14,6 +14,12 @@ var (
 		Use:   "scan",
 		Short: "Use this command to initiate secrets scan",
 		Long:  "Use this command to initiate secrets scan - v" + version.AppVersion(),
+		// only the closest persistent pre-run is called, so this one has to call the root one
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cmd.Root().PersistentPreRun(cmd, args)
+			// failed scans and findings over the failure threshold are not usage errors
+			cmd.SilenceUsage = true
+		},
 	}
 )
 
@@ -23,8 +29,42 @@ func init() {
 	viper.BindPFlag("global.bind-address", ScanCmd.PersistentFlags().Lookup("bind-address")) //nolint:errcheck
 	ScanCmd.PersistentFlags().Int("bind-port", 9393, "The port for the webserver")
 	viper.BindPFlag("global.bind-port", ScanCmd.PersistentFlags().Lookup("bind-port")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Int("archive-depth", 3, "How many nested layers of zip, jar, whl, nupkg and tar archives to scan the entries of, 0 disables scanning archives")
+	viper.BindPFlag("global.archive-depth", ScanCmd.PersistentFlags().Lookup("archive-depth")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Int("archive-max-entries", 10000, "Max number of entries read from an archive, nested archives included")
+	viper.BindPFlag("global.archive-max-entries", ScanCmd.PersistentFlags().Lookup("archive-max-entries")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Int("archive-max-size", 100, "Max number of bytes extracted from an archive, nested archives included (in MB)")
+	viper.BindPFlag("global.archive-max-size", ScanCmd.PersistentFlags().Lookup("archive-max-size")) //nolint:errcheck
 	ScanCmd.PersistentFlags().Int("confidence-level", 3, "The confidence level of the expressions used to find matches")
 	viper.BindPFlag("global.confidence-level", ScanCmd.PersistentFlags().Lookup("confidence-level")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Int("decode-depth", 0, "How many nested layers of base64, hex and URL-encoded blobs to decode before matching, decoding is disabled by default")
+	viper.BindPFlag("global.decode-depth", ScanCmd.PersistentFlags().Lookup("decode-depth")) //nolint:errcheck
+	ScanCmd.PersistentFlags().StringSlice("enable-signatures", nil, "Signature ids or glob patterns over id/description to enable, regardless of their enable flag and confidence level")
+	viper.BindPFlag("signatures.enable", ScanCmd.PersistentFlags().Lookup("enable-signatures")) //nolint:errcheck
+	ScanCmd.PersistentFlags().StringSlice("disable-signatures", nil, "Signature ids or glob patterns over id/description to disable, takes precedence over enable-signatures")
+	viper.BindPFlag("signatures.disable", ScanCmd.PersistentFlags().Lookup("disable-signatures")) //nolint:errcheck
+	ScanCmd.PersistentFlags().String("severity", "", "Only use signatures with this severity or higher (info, low, medium, high, critical)")
+	viper.BindPFlag("signatures.severity", ScanCmd.PersistentFlags().Lookup("severity")) //nolint:errcheck
+	ScanCmd.PersistentFlags().StringSlice("tags", nil, "Only use signatures with any of these tags or categories (glob patterns)")
+	viper.BindPFlag("signatures.tags", ScanCmd.PersistentFlags().Lookup("tags")) //nolint:errcheck
+	ScanCmd.PersistentFlags().String("fail-on-severity", "", "Exit with code 2 if there are findings with this severity or higher")
+	viper.BindPFlag("global.fail-on-severity", ScanCmd.PersistentFlags().Lookup("fail-on-severity")) //nolint:errcheck
+	ScanCmd.PersistentFlags().StringSlice("fail-on-tags", nil, "Exit with code 2 if there are findings with any of these tags or categories (glob patterns)")
+	viper.BindPFlag("global.fail-on-tags", ScanCmd.PersistentFlags().Lookup("fail-on-tags")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Bool("verify", false, "Check whether the secrets matched by signatures with a verifier are live credentials")
+	viper.BindPFlag("verify.enabled", ScanCmd.PersistentFlags().Lookup("verify")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Int("verify-timeout", 10, "Timeout in seconds for verifying a single secret")
+	viper.BindPFlag("verify.timeout", ScanCmd.PersistentFlags().Lookup("verify-timeout")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Bool("ssh", false, "Clone the repositories of providers over ssh")
+	viper.BindPFlag("clone.ssh", ScanCmd.PersistentFlags().Lookup("ssh")) //nolint:errcheck
+	ScanCmd.PersistentFlags().String("ssh-key", "", "Private key file for ssh clones, the ssh agent is used without one")
+	viper.BindPFlag("clone.ssh-key", ScanCmd.PersistentFlags().Lookup("ssh-key")) //nolint:errcheck
+	ScanCmd.PersistentFlags().String("known-hosts", "", "Known hosts file to verify the host keys of ssh clones against (default ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts)")
+	viper.BindPFlag("clone.known-hosts", ScanCmd.PersistentFlags().Lookup("known-hosts")) //nolint:errcheck
+	ScanCmd.PersistentFlags().Bool("credential-helper", false, "Get the credentials of https clones from the git credential helpers instead of using the token")
+	viper.BindPFlag("clone.credential-helper", ScanCmd.PersistentFlags().Lookup("credential-helper")) //nolint:errcheck
+	ScanCmd.PersistentFlags().String("netrc", "", "Get the credentials of https clones from this netrc file, as in ~/.netrc, instead of using the token")
+	viper.BindPFlag("clone.netrc", ScanCmd.PersistentFlags().Lookup("netrc")) //nolint:errcheck
 	ScanCmd.PersistentFlags().Bool("csv", false, "Output csv format")
 	viper.BindPFlag("global.csv", ScanCmd.PersistentFlags().Lookup("csv")) //nolint:errcheck
 	ScanCmd.PersistentFlags().Bool("json", false, "Output json format")
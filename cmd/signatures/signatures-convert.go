@@ -0,0 +1,29 @@
+package signatures
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/signatures"
+	"github.com/spf13/cobra"
+)
+
+// signaturesConvertCmd represents the command that converts other scanners' rules into signatures
+var signaturesConvertCmd = &cobra.Command{
+	Use:          "convert <file>",
+	Short:        "Convert gitleaks (.toml) or trufflehog custom detector rules into signatures",
+	Long:         "Convert gitleaks (.toml) or trufflehog custom detector rules into signatures. Rule options that can't be expressed as signatures are reported as warnings.",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.Load(api.Signatures); err != nil {
+			return err
+		}
+		output, _ := cmd.Flags().GetString("output")
+		return signatures.Convert(args[0], output)
+	},
+}
+
+func init() {
+	signaturesConvertCmd.Flags().StringP("output", "o", "", "File to write the signatures to, defaults to stdout")
+	SignaturesCmd.AddCommand(signaturesConvertCmd)
+}
@@ -0,0 +1,33 @@
+package signatures
+
+import (
+	"strings"
+
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/signatures"
+	"github.com/spf13/cobra"
+)
+
+// signaturesExportCmd represents the command that writes the signatures in other scanners' formats
+var signaturesExportCmd = &cobra.Command{
+	Use:          "export",
+	Short:        "Export the enabled signatures, with their tests, in other scanners' formats",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := inspectOptions(cmd)
+		if err != nil {
+			return err
+		}
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		return signatures.Export(opts.files, opts.level, opts.sel, format, output)
+	},
+}
+
+func init() {
+	addInspectFlags(signaturesExportCmd)
+	signaturesExportCmd.Flags().StringP("format", "f", coresignatures.FormatYAML, "Output format, one of "+strings.Join(coresignatures.ExportFormats, ", "))
+	signaturesExportCmd.Flags().StringP("output", "o", "", "File to write the signatures to, defaults to stdout")
+	SignaturesCmd.AddCommand(signaturesExportCmd)
+}
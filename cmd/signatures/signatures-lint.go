@@ -0,0 +1,36 @@
+package signatures
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/signatures"
+	"github.com/spf13/cobra"
+)
+
+// signaturesLintCmd represents the command that validates signature files
+var signaturesLintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Validate signature files and report invalid or slow signatures",
+	Long:  "Validate signature files and report invalid or slow signatures. Without arguments all the configured signature files are validated.",
+	// lint errors are not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(api.Signatures)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			return signatures.Lint(args)
+		}
+		files, err := coresignatures.Sources(cfg.Signatures.Path, cfg.Signatures.File)
+		if err != nil {
+			return err
+		}
+		return signatures.Lint(files)
+	},
+}
+
+func init() {
+	SignaturesCmd.AddCommand(signaturesLintCmd)
+}
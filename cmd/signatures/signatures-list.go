@@ -0,0 +1,108 @@
+package signatures
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/signatures"
+	"github.com/spf13/cobra"
+)
+
+// signaturesListCmd represents the command that lists the configured signatures
+var signaturesListCmd = &cobra.Command{
+	Use:          "list",
+	Aliases:      []string{"ls"},
+	Short:        "List the configured signatures and whether they are enabled",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := inspectOptions(cmd)
+		if err != nil {
+			return err
+		}
+		return signatures.List(opts.files, opts.level, opts.sel, opts.json)
+	},
+}
+
+// signaturesShowCmd represents the command that prints the details of a single signature
+var signaturesShowCmd = &cobra.Command{
+	Use:          "show <id>",
+	Short:        "Show the details of a single signature",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := inspectOptions(cmd)
+		if err != nil {
+			return err
+		}
+		return signatures.Show(opts.files, args[0], opts.level, opts.sel, opts.json)
+	},
+}
+
+type inspectOpts struct {
+	files []string
+	sel   coresignatures.Selection
+	level int
+	json  bool
+}
+
+// inspectOptions will resolve the signature files and output options of the list and show commands.
+// The flags are read directly as their viper keys are already bound by the scan command.
+func inspectOptions(cmd *cobra.Command) (inspectOpts, error) {
+	cfg, err := config.Load(api.Signatures)
+	if err != nil {
+		return inspectOpts{}, err
+	}
+	opts := inspectOpts{
+		level: cfg.Global.ConfidenceLevel,
+		sel: coresignatures.Selection{
+			Severity: cfg.Signatures.Severity,
+			Enable:   cfg.Signatures.Enable,
+			Disable:  cfg.Signatures.Disable,
+			Tags:     cfg.Signatures.Tags,
+		},
+	}
+	opts.files, err = coresignatures.Sources(cfg.Signatures.Path, cfg.Signatures.File)
+	if err != nil {
+		return inspectOpts{}, err
+	}
+	flags := cmd.Flags()
+	if flags.Changed("confidence-level") {
+		opts.level, _ = flags.GetInt("confidence-level")
+	}
+	if flags.Changed("enable-signatures") {
+		opts.sel.Enable, _ = flags.GetStringSlice("enable-signatures")
+	}
+	if flags.Changed("disable-signatures") {
+		opts.sel.Disable, _ = flags.GetStringSlice("disable-signatures")
+	}
+	if flags.Changed("severity") {
+		opts.sel.Severity, _ = flags.GetString("severity")
+	}
+	if flags.Changed("tags") {
+		opts.sel.Tags, _ = flags.GetStringSlice("tags")
+	}
+	opts.sel.Severity, err = coresignatures.ParseSeverity(opts.sel.Severity)
+	if err != nil {
+		return inspectOpts{}, err
+	}
+	opts.json, _ = flags.GetBool("json")
+	return opts, nil
+}
+
+// addInspectFlags will add the flags read by inspectOptions to the command
+func addInspectFlags(c *cobra.Command) {
+	c.Flags().Int("confidence-level", config.DefaultConfig.Global.ConfidenceLevel, "The minimum confidence level of the signatures to enable")
+	c.Flags().StringSlice("enable-signatures", nil, "Signature ids or glob patterns over id/description to enable")
+	c.Flags().StringSlice("disable-signatures", nil, "Signature ids or glob patterns over id/description to disable")
+	c.Flags().String("severity", "", "The minimum severity of the signatures to enable")
+	c.Flags().StringSlice("tags", nil, "Only enable signatures with any of these tags or categories")
+}
+
+func init() {
+	for _, c := range []*cobra.Command{signaturesListCmd, signaturesShowCmd} {
+		addInspectFlags(c)
+		c.Flags().Bool("json", false, "Output json format")
+		SignaturesCmd.AddCommand(c)
+	}
+}
@@ -0,0 +1,27 @@
+package signatures
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/signatures"
+	"github.com/spf13/cobra"
+)
+
+// signaturesTestCmd represents the command that runs the examples embedded in a signatures file
+var signaturesTestCmd = &cobra.Command{
+	Use:   "test <file>",
+	Short: "Run the match/nomatch examples embedded in a signatures file",
+	Args:  cobra.ExactArgs(1),
+	// a failing test is not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.Load(api.Signatures); err != nil {
+			return err
+		}
+		return signatures.Test(args[0])
+	},
+}
+
+func init() {
+	SignaturesCmd.AddCommand(signaturesTestCmd)
+}
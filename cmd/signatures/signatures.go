@@ -0,0 +1,15 @@
+// Package signatures represents the commands used to work with the detection signatures. Only specific code
+// related to the command should be in these files. As much of the code as possible should be pushed to other packages.
+package signatures
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	SignaturesCmd = &cobra.Command{
+		Use:     "signatures",
+		Aliases: []string{"sigs"},
+		Short:   "Use this command to inspect and test detection signatures",
+	}
+)
@@ -12,6 +12,7 @@ require (
 	github.com/migueleliasweb/go-github-mock v0.0.21
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/otiai10/copy v1.12.0
+	github.com/pelletier/go-toml/v2 v2.0.8
 	github.com/rumenvasilev/go-gitlab-mock v0.0.1
 	github.com/smartystreets/goconvey v1.8.1
 	github.com/spf13/cobra v1.7.0
@@ -19,6 +20,7 @@ require (
 	github.com/stretchr/testify v1.8.3
 	github.com/whilp/git-urls v1.0.0
 	github.com/xanzy/go-gitlab v0.93.2
+	golang.org/x/crypto v0.15.0
 	golang.org/x/oauth2 v0.11.0
 	golang.org/x/sync v0.5.0
 	gopkg.in/src-d/go-git.v4 v4.13.1
@@ -59,7 +61,6 @@ require (
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
-	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rs/zerolog v1.29.1 // indirect
 	github.com/sergi/go-diff v1.0.0 // indirect
@@ -74,7 +75,6 @@ require (
 	github.com/ugorji/go/codec v1.2.11 // indirect
 	github.com/xanzy/ssh-agent v0.2.1 // indirect
 	golang.org/x/arch v0.4.0 // indirect
-	golang.org/x/crypto v0.15.0 // indirect
 	golang.org/x/net v0.18.0 // indirect
 	golang.org/x/sys v0.14.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
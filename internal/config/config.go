@@ -30,48 +30,73 @@ var defaultIgnoreExtensions = []string{"jpg", "jpeg", "png", "gif", "bmp", "tiff
 var defaultIgnorePaths = []string{"node_modules/", "vendor/bundle", "vendor/cache", "/proc/"}
 
 type Config struct {
-	Github     Github     `mapstructure:"github" yaml:"github"`
-	Local      Local      `mapstructure:"local" yaml:"local"`
-	Signatures Signatures `mapstructure:"signatures" yaml:"signatures"`
+	AzureDevOps AzureDevOps `mapstructure:"azure-devops" yaml:"azure-devops"`
+	Bitbucket   Bitbucket   `mapstructure:"bitbucket" yaml:"bitbucket"`
+	Clone       Clone       `mapstructure:"clone" yaml:"clone"`
+	Gitea       Gitea       `mapstructure:"gitea" yaml:"gitea"`
+	Github      Github      `mapstructure:"github" yaml:"github"`
+	Local       Local       `mapstructure:"local" yaml:"local"`
+	Remotes     Remotes     `mapstructure:"remotes" yaml:"remotes"`
+	Signatures  Signatures  `mapstructure:"signatures" yaml:"signatures"`
 
 	Gitlab Gitlab `mapstructure:"gitlab" yaml:"gitlab"`
 	Global Global `mapstructure:"global" yaml:"global"`
+	Verify Verify `mapstructure:"verify" yaml:"verify"`
 }
 
 type Global struct {
-	AppVersion      string       `yaml:"-"`
-	BindAddress     string       `mapstructure:"bind-address" structs:"bind-address" yaml:"bind-address"`
-	ConfigFile      string       `mapstructure:"config-file" structs:"config-file" yaml:"-"`
-	ScanType        api.ScanType `mapstructure:"scan-type" structs:"scan-type" yaml:"-"`
-	SkippableExt    []string     `mapstructure:"ignore-extension" structs:"ignore-extension" yaml:"ignore-extension"`
-	SkippablePath   []string     `mapstructure:"ignore-path" structs:"ignore-path" yaml:"ignore-path"`
-	BindPort        int          `mapstructure:"bind-port" structs:"bind-port" yaml:"bind-port"`
-	CommitDepth     int          `mapstructure:"commit-depth" structs:"commit-depth" yaml:"commit-depth"`
-	ConfidenceLevel int          `mapstructure:"confidence-level" structs:"confidence-level" yaml:"confidence-level"`
-	MaxFileSize     int64        `mapstructure:"max-file-size" structs:"max-file-size" yaml:"max-file-size"`
-	Threads         int          `mapstructure:"num-threads" structs:"num-threads" yaml:"num-threads"`
-	CSVOutput       bool         `mapstructure:"csv"`
-	Debug           bool         `mapstructure:"debug"`
-	ExpandOrgs      bool         `mapstructure:"expand-orgs" structs:"expand-orgs" yaml:"expand-orgs"`
-	HideSecrets     bool         `mapstructure:"hide-secrets" structs:"hide-secrets" yaml:"hide-secrets"`
-	InMemClone      bool         `mapstructure:"in-mem-clone" structs:"in-mem-clone" yaml:"in-mem-clone"`
-	JSONOutput      bool         `mapstructure:"json" structs:"json"`
-	ScanFork        bool         `mapstructure:"scan-forks" structs:"scan-forks" yaml:"scan-forks"`
-	ScanTests       bool         `mapstructure:"scan-tests" structs:"scan-tests" yaml:"scan-tests"`
-	Silent          bool         `mapstructure:"silent"`
-	WebServer       bool         `mapstructure:"web-server" structs:"web-server" yaml:"web-server"`
-	_               [6]byte
+	AppVersion        string       `yaml:"-"`
+	BindAddress       string       `mapstructure:"bind-address" structs:"bind-address" yaml:"bind-address"`
+	ConfigFile        string       `mapstructure:"config-file" structs:"config-file" yaml:"-"`
+	FailOnSeverity    string       `mapstructure:"fail-on-severity" structs:"fail-on-severity" yaml:"fail-on-severity,omitempty"`
+	ScanType          api.ScanType `mapstructure:"scan-type" structs:"scan-type" yaml:"-"`
+	FailOnTags        []string     `mapstructure:"fail-on-tags" structs:"fail-on-tags" yaml:"fail-on-tags,omitempty"`
+	SkippableExt      []string     `mapstructure:"ignore-extension" structs:"ignore-extension" yaml:"ignore-extension"`
+	SkippablePath     []string     `mapstructure:"ignore-path" structs:"ignore-path" yaml:"ignore-path"`
+	ArchiveDepth      int          `mapstructure:"archive-depth" structs:"archive-depth" yaml:"archive-depth"`
+	ArchiveMaxEntries int          `mapstructure:"archive-max-entries" structs:"archive-max-entries" yaml:"archive-max-entries"`
+	ArchiveMaxSize    int64        `mapstructure:"archive-max-size" structs:"archive-max-size" yaml:"archive-max-size"`
+	BindPort          int          `mapstructure:"bind-port" structs:"bind-port" yaml:"bind-port"`
+	CommitDepth       int          `mapstructure:"commit-depth" structs:"commit-depth" yaml:"commit-depth"`
+	ConfidenceLevel   int          `mapstructure:"confidence-level" structs:"confidence-level" yaml:"confidence-level"`
+	DecodeDepth       int          `mapstructure:"decode-depth" structs:"decode-depth" yaml:"decode-depth"`
+	MaxFileSize       int64        `mapstructure:"max-file-size" structs:"max-file-size" yaml:"max-file-size"`
+	Threads           int          `mapstructure:"num-threads" structs:"num-threads" yaml:"num-threads"`
+	CSVOutput         bool         `mapstructure:"csv"`
+	Debug             bool         `mapstructure:"debug"`
+	ExpandOrgs        bool         `mapstructure:"expand-orgs" structs:"expand-orgs" yaml:"expand-orgs"`
+	HideSecrets       bool         `mapstructure:"hide-secrets" structs:"hide-secrets" yaml:"hide-secrets"`
+	InMemClone        bool         `mapstructure:"in-mem-clone" structs:"in-mem-clone" yaml:"in-mem-clone"`
+	JSONOutput        bool         `mapstructure:"json" structs:"json"`
+	ScanFork          bool         `mapstructure:"scan-forks" structs:"scan-forks" yaml:"scan-forks"`
+	ScanTests         bool         `mapstructure:"scan-tests" structs:"scan-tests" yaml:"scan-tests"`
+	Silent            bool         `mapstructure:"silent"`
+	WebServer         bool         `mapstructure:"web-server" structs:"web-server" yaml:"web-server"`
+	_                 [6]byte
 }
 
 type Signatures struct {
-	APIToken string `mapstructure:"api-token" structs:"api-token" yaml:"api-token"`
-	File     string `mapstructure:"file"`
-	Path     string `mapstructure:"path"`
-	URL      string `mapstructure:"url"`
-	UserRepo string `mapstructure:"user-repo" yaml:"user-repo"`
-	Version  string `mapstructure:"version"`
-	Test     bool   `mapstructure:"test"`
-	_        [31]byte
+	APIToken string   `mapstructure:"api-token" structs:"api-token" yaml:"api-token"`
+	Path     string   `mapstructure:"path"`
+	Severity string   `mapstructure:"severity" yaml:"severity,omitempty"`
+	URL      string   `mapstructure:"url"`
+	UserRepo string   `mapstructure:"user-repo" yaml:"user-repo"`
+	Version  string   `mapstructure:"version"`
+	File     []string `mapstructure:"file" yaml:"file,omitempty"`
+	Enable   []string `mapstructure:"enable" yaml:"enable,omitempty"`
+	Disable  []string `mapstructure:"disable" yaml:"disable,omitempty"`
+	Tags     []string `mapstructure:"tags" yaml:"tags,omitempty"`
+	Test     bool     `mapstructure:"test"`
+	_        [7]byte
+}
+
+// Verify holds the options of checking whether the secrets found are live credentials
+type Verify struct {
+	// Endpoints overrides the endpoint of a verifier, keyed by the verifier name or signature id
+	Endpoints map[string]string `mapstructure:"endpoints" structs:"endpoints" yaml:"endpoints,omitempty"`
+	Timeout   int               `mapstructure:"timeout" structs:"timeout" yaml:"timeout"` // seconds, per secret
+	Enabled   bool              `mapstructure:"enabled" structs:"enabled" yaml:"enabled"`
+	_         [7]byte
 }
 
 type Github struct {
@@ -85,16 +110,73 @@ type Github struct {
 	_                   [7]byte
 }
 
+type Bitbucket struct {
+	APIToken string   `mapstructure:"api-token" structs:"api-token" yaml:"api-token"`
+	URL      string   `mapstructure:"url" structs:"url" yaml:"url,omitempty"`                // Bitbucket Server and Data Center, Bitbucket Cloud when empty
+	Username string   `mapstructure:"username" structs:"username" yaml:"username,omitempty"` // the token is an app password or a personal access token when set
+	Targets  []string `mapstructure:"workspaces" structs:"workspaces" yaml:"workspaces,omitempty"`
+	_        [56]byte
+}
+
+type AzureDevOps struct {
+	APIToken string   `mapstructure:"api-token" structs:"api-token" yaml:"api-token"`
+	URL      string   `mapstructure:"url" structs:"url" yaml:"url,omitempty"` // Azure DevOps Server, dev.azure.com when empty
+	Targets  []string `mapstructure:"organizations" structs:"organizations" yaml:"organizations,omitempty"`
+	_        [8]byte
+}
+
+// Clone is how the repositories are cloned, over ssh or with other credentials than the token of the provider
+type Clone struct {
+	KnownHosts       string `mapstructure:"known-hosts" structs:"known-hosts" yaml:"known-hosts,omitempty"` // ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts when empty
+	Netrc            string `mapstructure:"netrc" structs:"netrc" yaml:"netrc,omitempty"`
+	SSHKey           string `mapstructure:"ssh-key" structs:"ssh-key" yaml:"ssh-key,omitempty"` // the ssh agent when empty
+	SSHKeyPassphrase string `mapstructure:"ssh-key-passphrase" structs:"ssh-key-passphrase" yaml:"ssh-key-passphrase,omitempty"`
+	CredentialHelper bool   `mapstructure:"credential-helper" structs:"credential-helper" yaml:"credential-helper,omitempty"`
+	SSH              bool   `mapstructure:"ssh" structs:"ssh" yaml:"ssh,omitempty"` // the ssh addresses of the repositories of providers
+	_                [62]byte
+}
+
+type Gitea struct {
+	APIToken string   `mapstructure:"api-token" structs:"api-token" yaml:"api-token"`
+	URL      string   `mapstructure:"url" structs:"url" yaml:"url"`
+	Targets  []string `mapstructure:"orgs" structs:"orgs" yaml:"orgs,omitempty"`
+	_        [8]byte
+}
+
 type Gitlab struct {
 	APIToken string   `mapstructure:"api-token" structs:"api-token" yaml:"api-token"`
+	URL      string   `mapstructure:"url" structs:"url" yaml:"url,omitempty"` // self-hosted instances, gitlab.com when empty
 	Targets  []string `mapstructure:"projects" structs:"projects" yaml:"projects,omitempty"`
-	_        [24]byte
+	_        [8]byte
 }
 
 type Local struct {
-	Paths []string `mapstructure:"paths" yaml:",omitempty"`
-	Repos []string `mapstructure:"repos" yaml:",omitempty"`
-	_     [16]byte
+	Exclude          []string `mapstructure:"exclude" yaml:",omitempty"` // glob patterns of the paths to skip, relative to the scanned directory
+	Images           []string `mapstructure:"images" yaml:",omitempty"`  // docker save tarballs and OCI image layouts
+	Include          []string `mapstructure:"include" yaml:",omitempty"` // glob patterns of the only paths to scan, relative to the scanned directory
+	Paths            []string `mapstructure:"paths" yaml:",omitempty"`
+	Repos            []string `mapstructure:"repos" yaml:",omitempty"`
+	StdinName        string   `mapstructure:"stdin-name" yaml:"stdin-name,omitempty"` // the data read from stdin is reported as a file of this name
+	Timeout          int      `mapstructure:"timeout" yaml:"timeout,omitempty"`       // seconds, 0 for no limit
+	RespectGitignore bool     `mapstructure:"respect-gitignore" yaml:"respect-gitignore,omitempty"`
+	_                [47]byte
+}
+
+// Remotes are git repositories cloned from their URLs, on hosts without a supported API
+type Remotes struct {
+	Credentials    []Credential          `mapstructure:"credentials" yaml:",omitempty"`
+	File           string                `mapstructure:"file" yaml:",omitempty"` // clone URLs, one per line
+	URLs           []string              `mapstructure:"urls" yaml:",omitempty"`
+	URLCredentials map[string]Credential `mapstructure:"-" structs:"-" yaml:"-"` // taken out of the URLs, by their clone URL
+	_              [56]byte
+}
+
+// Credential is the username and password, or token, for cloning from a host over http
+type Credential struct {
+	Host     string `mapstructure:"host" yaml:"host"` // with the port when it's not the default one
+	Username string `mapstructure:"username" yaml:"username"`
+	Password string `mapstructure:"password" yaml:"password"`
+	_        [16]byte
 }
 
 func (c Config) toYaml() string {
@@ -109,6 +191,11 @@ func isEmpty(input interface{}) bool {
 	// check all iterables, the rest have defaults
 	if v, ok := input.([]string); ok {
 		return len(v) == 0
+	} else if v, ok := input.(map[string]string); ok {
+		return len(v) == 0
+	} else if v, ok := input.([]interface{}); ok {
+		// slices of structs, as the remote credentials
+		return len(v) == 0
 	} else if v, ok := input.(string); ok {
 		return len(v) == 0
 		// } else if _, ok := input.(int); ok {
@@ -216,10 +303,43 @@ func Load(scanType api.ScanType) (*Config, error) {
 		if cfg.Gitlab.APIToken == "" {
 			return nil, errors.New("APIToken for Gitlab is not set")
 		}
+	case api.Bitbucket:
+		if cfg.Bitbucket.APIToken == "" {
+			return nil, errors.New("APIToken for Bitbucket is not set")
+		}
+	case api.AzureDevOps:
+		if cfg.AzureDevOps.APIToken == "" {
+			return nil, errors.New("APIToken for Azure DevOps is not set")
+		}
+	case api.Gitea:
+		if cfg.Gitea.URL == "" {
+			return nil, errors.New("Gitea URL is not set")
+		}
+		if cfg.Gitea.APIToken == "" {
+			return nil, errors.New("APIToken for Gitea is not set")
+		}
 	}
 	return cfg, nil
 }
 
+// ServerURL returns the address of the self-hosted git server the scan targets, empty for the public
+// ones
+func (c Config) ServerURL() string {
+	switch c.Global.ScanType {
+	case api.GithubEnterprise:
+		return c.Github.GithubEnterpriseURL
+	case api.Gitlab:
+		return c.Gitlab.URL
+	case api.Bitbucket:
+		return c.Bitbucket.URL
+	case api.AzureDevOps:
+		return c.AzureDevOps.URL
+	case api.Gitea:
+		return c.Gitea.URL
+	}
+	return ""
+}
+
 // setCommitDepth will set the commit depth for the current session. This is an ugly way of doing it
 // but for the moment it works fine.
 // TODO dynamically acquire the commit depth of a given repo
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
 	"github.com/rumenvasilev/rvsecret/internal/util"
 	"github.com/rumenvasilev/rvsecret/version"
@@ -70,11 +71,13 @@ func getTestDefaultConfig(t *testing.T, cfgFile bool) Config {
 		tmp := DefaultConfig
 		tmp.Global.ConfigFile = f.Name()
 		tmp.Github.APIToken = "testing"
+		tmp.Remotes.Credentials = []Credential{{Host: "git.example.com", Username: "jane", Password: "testing"}}
 		// populate with settings from default
 		f.Write([]byte(tmp.toYaml()))
 		f.Close()
 		DefaultConfig.Global.ConfigFile = f.Name()
 		DefaultConfig.Github.APIToken = "testing"
+		DefaultConfig.Remotes.Credentials = tmp.Remotes.Credentials
 	}
 	c := DefaultConfig
 	c.Global.SkippablePath = util.AppendToSlice(true, defaultIgnorePaths, global.SkippablePath)
@@ -108,6 +111,12 @@ func TestLoad(t *testing.T) {
 		{"GithubEnterprise_E", args{api.GithubEnterprise, Config{Github: Github{APIToken: "la", GithubEnterpriseURL: ""}}}, Config{}, "Github enterprise URL is not set"},
 		{"Gitlab", args{api.Gitlab, Config{Gitlab: Gitlab{APIToken: "bla"}}}, Config{Global: Global{ScanType: api.Gitlab}, Gitlab: Gitlab{APIToken: "bla"}}, ""},
 		{"Gitlab_E", args{api.Gitlab, Config{Gitlab: Gitlab{APIToken: ""}}}, Config{}, "APIToken for Gitlab is not set"},
+		{"AzureDevOps", args{api.AzureDevOps, Config{AzureDevOps: AzureDevOps{APIToken: "bla"}}}, Config{Global: Global{ScanType: api.AzureDevOps}, AzureDevOps: AzureDevOps{APIToken: "bla"}}, ""},
+		{"AzureDevOps_E", args{api.AzureDevOps, Config{AzureDevOps: AzureDevOps{}}}, Config{}, "APIToken for Azure DevOps is not set"},
+		{"GitRemotes", args{api.GitRemotes, Config{Remotes: Remotes{File: "remotes.txt"}}}, Config{Global: Global{ScanType: api.GitRemotes}, Remotes: Remotes{File: "remotes.txt"}}, ""},
+		{"Gitea", args{api.Gitea, Config{Gitea: Gitea{APIToken: "bla", URL: "https://gitea.example.com"}}}, Config{Global: Global{ScanType: api.Gitea}, Gitea: Gitea{APIToken: "bla", URL: "https://gitea.example.com"}}, ""},
+		{"Gitea_E_URL", args{api.Gitea, Config{Gitea: Gitea{APIToken: "bla"}}}, Config{}, "Gitea URL is not set"},
+		{"Gitea_E", args{api.Gitea, Config{Gitea: Gitea{URL: "https://gitea.example.com"}}}, Config{}, "APIToken for Gitea is not set"},
 		{"UpdateSignatures", args{api.UpdateSignatures, Config{Signatures: Signatures{APIToken: "bla"}}}, Config{Global: Global{ScanType: api.UpdateSignatures}, Signatures: Signatures{APIToken: "bla"}}, ""},
 		{"UpdateSignatures_E", args{api.UpdateSignatures, Config{Signatures: Signatures{APIToken: ""}}}, Config{}, "APIToken for Github is not set"},
 	}
@@ -125,6 +134,23 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+// the default ignore paths were written for substring matching, they must still match whole directories
+func TestDefaultIgnorePaths(t *testing.T) {
+	paths := map[string]string{
+		"node_modules/": "/src/app/node_modules/left-pad/index.js",
+		"vendor/bundle": "vendor/bundle/ruby/3.2.0/gems/rake/lib/rake.rb",
+		"vendor/cache":  "/src/vendor/cache/rake-13.0.6.gem",
+		"/proc/":        "/proc/self/environ",
+	}
+	assert.Len(t, paths, len(defaultIgnorePaths))
+	for _, v := range defaultIgnorePaths {
+		path, ok := paths[v]
+		if assert.True(t, ok, v) {
+			assert.True(t, matchfile.HasSkippablePath(path, []string{v}), v)
+		}
+	}
+}
+
 func Test_setCommitDepth(t *testing.T) {
 	type args struct {
 		c int
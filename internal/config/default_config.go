@@ -4,7 +4,6 @@ var signatures = Signatures{
 	URL:     "https://github.com/rumenvasilev/rvsecret-signatures",
 	Version: "latest",
 	Test:    true,
-	File:    "$HOME/.rvsecret/signatures/default.yaml",
 	Path:    "$HOME/.rvsecret/signatures",
 }
 
@@ -23,17 +22,25 @@ var gitlab = Gitlab{
 var global = Global{
 	BindAddress: "127.0.0.1",
 	// ConfigFile:      "none", // default defined as const in config.go
-	BindPort:        9393,
-	CommitDepth:     -1,
-	ConfidenceLevel: 3,
-	MaxFileSize:     10,
-	Threads:         -1,
-	HideSecrets:     false,
-	InMemClone:      false,
-	ScanFork:        false,
-	ScanTests:       false,
-	Silent:          false,
-	WebServer:       false,
+	ArchiveDepth:      3,
+	ArchiveMaxEntries: 10000,
+	ArchiveMaxSize:    100,
+	BindPort:          9393,
+	CommitDepth:       -1,
+	ConfidenceLevel:   3,
+	DecodeDepth:       0,
+	MaxFileSize:       10,
+	Threads:           -1,
+	HideSecrets:       false,
+	InMemClone:        false,
+	ScanFork:          false,
+	ScanTests:         false,
+	Silent:            false,
+	WebServer:         false,
+}
+
+var verify = Verify{
+	Timeout: 10,
 }
 
 var DefaultConfig = Config{
@@ -41,4 +48,5 @@ var DefaultConfig = Config{
 	Github:     github,
 	Gitlab:     gitlab,
 	Global:     global,
+	Verify:     verify,
 }
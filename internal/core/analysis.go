@@ -3,17 +3,23 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/rumenvasilev/rvsecret/internal/config"
 	coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/archive"
+	"github.com/rumenvasilev/rvsecret/internal/core/extract"
 	"github.com/rumenvasilev/rvsecret/internal/core/finding"
 	"github.com/rumenvasilev/rvsecret/internal/core/git"
 	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
+	"github.com/rumenvasilev/rvsecret/internal/core/privatekey"
 	"github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/core/verify"
 	"github.com/rumenvasilev/rvsecret/internal/log"
 	"github.com/rumenvasilev/rvsecret/internal/session"
 	"github.com/rumenvasilev/rvsecret/internal/stats"
@@ -175,27 +181,64 @@ func isDirtyCommit(ctx context.Context, sess *session.Session, commit *object.Co
 	return dirtyCommit
 }
 
+// AnalyzeObject will scan the file, or the entries of the archive, at the given path. For changes of git
+// commits the path is the root of the clone.
 func AnalyzeObject(ctx context.Context, sess *session.Session, change *object.Change, commit *object.Commit, filepath string, repo coreapi.Repository) bool {
+	fPath := filepath
+	if change != nil {
+		fPath = git.GetChangePath(change)
+		filepath += fmt.Sprintf("/%s", fPath)
+	}
+	return analyzeFile(ctx, sess, source{change: change, commit: commit, repo: repo}, matchfile.New(filepath), fPath)
+}
+
+// AnalyzeContent will scan the data as the content of a file at the given path, which doesn't need to exist.
+// Unlike files, the data is scanned even if the path is ignored or the data is binary.
+func AnalyzeContent(ctx context.Context, sess *session.Session, path string, data []byte) bool {
+	mf := matchfile.New(path)
+	mf.Content = data
+	return analyzeFile(ctx, sess, source{given: true}, mf, path)
+}
+
+// source is where a file comes from, e.g. a change of a git commit or the layer of an image
+type source struct {
+	change *object.Change
+	commit *object.Commit
+	repo   coreapi.Repository
+	action string // overrides the action of the change
+	layer  string
+	given  bool // the data was given by the user, e.g. on stdin, and is never ignored
+}
+
+type archiveKey int
+
+// inArchive is set on the context of the entries of archives, which are read nested already
+const inArchive archiveKey = 0
+
+// analyzeFile will scan the file, whose path is reported as fPath. The file is read from disk, unless the
+// match file holds its content.
+func analyzeFile(ctx context.Context, sess *session.Session, src source, mf matchfile.MatchFile, fPath string) bool {
 	log := log.Log
 	tid := ctx.Value(TID)
 	cfg := sess.Config
-	fPath := filepath
+	change, commit, repo := src.change, src.commit, src.repo
 
 	// The total number of files that were evaluated
 	sess.State.Stats.IncrementFilesTotal()
 
-	var changeAction string
+	changeAction := src.action
 	if change != nil {
 		changeAction = git.GetChangeAction(change)
-		fPath = git.GetChangePath(change)
-		filepath += fmt.Sprintf("/%s", fPath)
 	}
 
-	// Break a file name up into its composite pieces including the extension and base name
-	mf := matchfile.New(filepath)
+	// archives of commits are read as they were in the commit, the working tree only has their last version
+	fromHistory := change != nil && mf.Content == nil && isArchive(ctx, cfg.Global, mf)
 
-	// Check if file has to be ignored
-	if ok, msg := isIgnoredFile(cfg.Global.ScanTests, cfg.Global.MaxFileSize, filepath, mf, cfg.Global.SkippableExt, cfg.Global.SkippablePath); ok {
+	// Check if file has to be ignored, the data given by the user is always scanned. The entries of its
+	// archives are not given as such.
+	given := src.given
+	src.given = false
+	if ok, msg := isIgnoredFile(ctx, cfg.Global, mf, fromHistory); ok && !given {
 		if change != nil {
 			log.Debug("[THREAD #%d][%s] %s %s", tid, repo.CloneURL, fPath, msg)
 		} else {
@@ -205,6 +248,22 @@ func AnalyzeObject(ctx context.Context, sess *session.Session, change *object.Ch
 		return false
 	}
 
+	if fromHistory {
+		data, err := git.GetChangeBlob(change, cfg.Global.ArchiveMaxSize*1024*1024)
+		if err != nil {
+			log.Debug("[THREAD #%d][%s] %s can't be read from the commit, %s", tid, repo.CloneURL, fPath, err.Error())
+			sess.State.Stats.IncrementIgnoredFiles()
+			return false
+		}
+		mf.Content = data
+	}
+	if isArchive(ctx, cfg.Global, mf) {
+		return analyzeArchive(ctx, sess, src, mf, fPath)
+	}
+	if extract.Format(mf.Path) != "" {
+		return analyzeDocument(ctx, sess, src, mf, fPath)
+	}
+
 	// We are now finally at the point where we are going to scan a file so we implement
 	// that count.
 	sess.State.Stats.IncrementScannedFiles()
@@ -215,6 +274,7 @@ func AnalyzeObject(ctx context.Context, sess *session.Session, change *object.Ch
 	tpl := finding.Finding{
 		Action:           changeAction,
 		FilePath:         fPath,
+		Layer:            src.layer,
 		AppVersion:       sess.Config.Global.AppVersion,
 		RepositoryName:   ``, // TODO do we need to set these 2 lines to nothing?
 		RepositoryOwner:  ``,
@@ -230,7 +290,7 @@ func AnalyzeObject(ctx context.Context, sess *session.Session, change *object.Ch
 		tpl.RepositoryOwner = repo.Owner
 	}
 	for _, v := range results {
-		generateFindings(sess, v, tpl)
+		generateFindings(ctx, sess, v, tpl)
 	}
 
 	if dirtyFile {
@@ -244,19 +304,82 @@ func AnalyzeObject(ctx context.Context, sess *session.Session, change *object.Ch
 	return dcommit
 }
 
-func isIgnoredFile(cfgScanTests bool, cfgMaxFileSize int64, fullFilePath string, mf matchfile.MatchFile, cfgSkippableExt, cfgSkippablePath []string) (bool, string) {
-	// Check if file exist before moving on
-	if !util.PathExists(fullFilePath) {
+// isArchive returns true if the file is an archive whose entries are scanned. Archives within archives
+// are read by the archive package.
+func isArchive(ctx context.Context, cfg config.Global, mf matchfile.MatchFile) bool {
+	return cfg.ArchiveDepth > 0 && ctx.Value(inArchive) == nil && archive.Format(mf.Path) != ""
+}
+
+// analyzeArchive will scan every entry of the archive as a file, reported as archive.zip!/inner/path
+func analyzeArchive(ctx context.Context, sess *session.Session, src source, mf matchfile.MatchFile, fPath string) bool {
+	cfg := sess.Config.Global
+	limits := archive.Limits{
+		MaxSize:      cfg.ArchiveMaxSize * 1024 * 1024,
+		MaxEntrySize: cfg.MaxFileSize * 1024 * 1024,
+		MaxEntries:   cfg.ArchiveMaxEntries,
+		MaxDepth:     cfg.ArchiveDepth,
+	}
+	dirtyCommit := false
+	entryCtx := context.WithValue(ctx, inArchive, true)
+	fn := func(e archive.Entry) {
+		entry := matchfile.New(mf.Path + archive.Separator + e.Path)
+		entry.Content = e.Data
+		if analyzeFile(entryCtx, sess, src, entry, fPath+archive.Separator+e.Path) {
+			dirtyCommit = true
+		}
+	}
+	var err error
+	if mf.Content != nil {
+		err = archive.WalkData(mf.Path, mf.Content, limits, fn)
+	} else {
+		err = archive.Walk(mf.Path, limits, fn)
+	}
+	if errors.Is(err, archive.ErrLimit) {
+		log.Log.Warn("%s: %s, the rest of the archive is skipped", fPath, err.Error())
+	} else if err != nil {
+		log.Log.Debug("[THREAD #%d] %s can't be read as an archive, %s", ctx.Value(TID), fPath, err.Error())
+	}
+	return dirtyCommit
+}
+
+// analyzeDocument will scan the text of every part of a notebook or office document as a file, reported
+// as notebook.ipynb!/cell-1
+func analyzeDocument(ctx context.Context, sess *session.Session, src source, mf matchfile.MatchFile, fPath string) bool {
+	data, err := mf.Read()
+	if err != nil {
+		log.Log.Debug("[THREAD #%d] %s can't be read, %s", ctx.Value(TID), fPath, err.Error())
+		return false
+	}
+	// the parts extracted before an error are still scanned
+	parts, err := extract.Extract(mf.Path, data, sess.Config.Global.MaxFileSize*1024*1024)
+	if err != nil {
+		log.Log.Debug("[THREAD #%d] %s can't be read as a document, %s", ctx.Value(TID), fPath, err.Error())
+	}
+	dirtyCommit := false
+	for _, part := range parts {
+		entry := matchfile.New(mf.Path + archive.Separator + part.Name)
+		entry.Content = []byte(part.Text)
+		if analyzeFile(ctx, sess, src, entry, fPath+archive.Separator+part.Name) {
+			dirtyCommit = true
+		}
+	}
+	return dirtyCommit
+}
+
+func isIgnoredFile(ctx context.Context, cfg config.Global, mf matchfile.MatchFile, fromHistory bool) (bool, string) {
+	fullFilePath := mf.Path
+	// Check if file exist before moving on, unless it's read from the commit
+	if mf.Content == nil && !fromHistory && !util.PathExists(fullFilePath) {
 		return true, "file does not exist"
 	}
 
 	// required as that is a map of interfaces.
 	// scanTests := DefaultValues["scan-tests"]
-	likelyTestFile := cfgScanTests
+	likelyTestFile := cfg.ScanTests
 
 	// If we do not want to scan tests we run some checks to see if the file in
 	// question is a test file. This will return a true if it is a test file.
-	if !cfgScanTests {
+	if !cfg.ScanTests {
 		likelyTestFile = util.IsTestFileOrPath(fullFilePath)
 	}
 
@@ -269,40 +392,85 @@ func isIgnoredFile(cfgScanTests bool, cfgMaxFileSize int64, fullFilePath string,
 		return true, "is a test file and is being ignored"
 	}
 
+	if mf.IsSkippable(cfg.SkippableExt, cfg.SkippablePath) {
+		return true, "is skippable, ignoring"
+	}
+
+	// Archives are limited by the archive options rather than the file size, and files that aren't on disk
+	// are already limited in size when they're read
+	if isArchive(ctx, cfg, mf) {
+		return false, ""
+	}
+	if mf.Content != nil {
+		if util.IsBinary(mf.Content) && !privatekey.IsKeystore(fullFilePath) && extract.Format(fullFilePath) == "" {
+			return true, "is a binary file, ignoring"
+		}
+		return false, ""
+	}
+
 	// Check the file size of the file. If it is greater than the default size then
 	// then we increment the ignored file count and pass on through.
-	if yes, msg := util.IsMaxFileSize(fullFilePath, cfgMaxFileSize); yes {
+	if yes, msg := util.IsMaxFileSize(fullFilePath, cfg.MaxFileSize); yes {
 		return true, msg
 	}
 
-	// Check if it is a binary file
+	// Check if it is a binary file, keystores are read by the private key detector and the text of
+	// documents is extracted
 	yes, err := util.IsBinaryFile(fullFilePath)
-	if yes || err != nil {
+	if err != nil || (yes && !privatekey.IsKeystore(fullFilePath) && extract.Format(fullFilePath) == "") {
 		return true, "is a binary file, ignoring"
 	}
 
-	if mf.IsSkippable(cfgSkippableExt, cfgSkippablePath) {
-		return true, "is skippable, ignoring"
-	}
-
 	return false, ""
 }
 
-func generateFindings(sess *session.Session, data signatures.DiscoverOutput, template finding.Finding) {
+func generateFindings(ctx context.Context, sess *session.Session, data signatures.DiscoverOutput, template finding.Finding) {
 	fin := template
 	fin.Content = data.Content
+	fin.Encoding = data.Encoding
+	fin.Encoded = data.Encoded
 	fin.Description = data.Sig.Description()
 	fin.LineNumber = strconv.Itoa(data.LineNum)
+	if data.EndLineNum > data.LineNum {
+		fin.EndLineNumber = strconv.Itoa(data.EndLineNum)
+	}
+	if data.Column > 0 {
+		fin.ColumnNumber = strconv.Itoa(data.Column)
+	}
 	fin.SignatureID = data.Sig.SignatureID()
+	fin.Severity = data.Sig.Severity()
+	if data.Severity != "" {
+		fin.Severity = data.Severity
+	}
+	fin.Fingerprint = data.Fingerprint
+	fin.KeyInfo = data.KeyInfo
+	fin.Category = data.Sig.Category()
+	fin.Tags = data.Sig.Tags()
 
 	// SecretID is used for dedup later under AddFinding()
 	params := []string{fin.RepositoryName, fin.FilePath, fin.LineNumber, fin.Content}
 	fin.SecretID = util.GenerateSecretIDWithParams(params...)
 
-	_ = fin.Initialize(sess.Config.Global.ScanType, sess.Config.Github.GithubEnterpriseURL)
+	_ = fin.Initialize(sess.Config.Global.ScanType, sess.Config.ServerURL())
+
+	if sess.Verifier != nil && data.Sig.Verifier() != nil {
+		// the finding is added once verified, which keeps going when the scan is cut short
+		sess.Verifier.Go(context.WithoutCancel(ctx), fin.SignatureID, *data.Sig.Verifier(), data.Secret, func(res verify.Result) {
+			if res.Message != "" {
+				log.Log.Debug("Verifying secret of %s in %s: %s", fin.SignatureID, fin.FilePath, res.Message)
+			}
+			fin.Verification = res.Status
+			addFinding(sess, &fin)
+		})
+		return
+	}
+	addFinding(sess, &fin)
+}
 
+// addFinding will add the finding to the session and print it, unless it was found already
+func addFinding(sess *session.Session, fin *finding.Finding) {
 	// Add it to the session
-	if sess.State.AddFinding(&fin) {
+	if sess.State.AddFinding(fin) {
 		// Print realtime data to stdout if finding was not a dup
 		fin.RealtimeOutput(sess.Config.Global)
 	}
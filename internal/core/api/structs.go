@@ -28,6 +28,7 @@ type Repository struct {
 	Name          string
 	FullName      string
 	CloneURL      string
+	SSHURL        string
 	URL           string
 	DefaultBranch string
 	Description   string // WHY DO WE NEED THIS FIELD???
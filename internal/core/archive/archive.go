@@ -0,0 +1,264 @@
+// Package archive reads the entries of zip and tar archives, including compressed and nested ones, so
+// that their content can be scanned like regular files. Archives are read in memory, without extracting
+// them to disk, and within limits that guard against archive bombs.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/log"
+)
+
+// Supported formats
+const (
+	FormatZip   = "zip"     // zip files and the packages based on them, e.g. .jar, .whl and .nupkg
+	FormatTar   = "tar"     // uncompressed tar files
+	FormatTarGz = "tar.gz"  // gzip compressed tar files
+	FormatTarBz = "tar.bz2" // bzip2 compressed tar files
+	FormatGzip  = "gz"      // a single gzip compressed file
+)
+
+// Separator is put between the path of an archive and the path of an entry within it, e.g. app.zip!/.env
+const Separator = "!/"
+
+// ErrLimit is returned when reading an archive exceeds one of the limits
+var ErrLimit = errors.New("archive limit exceeded")
+
+// Limits guard against archive bombs. The size and number of entries are counted across the archive and
+// the archives nested in it.
+type Limits struct {
+	MaxSize      int64 // total number of bytes extracted
+	MaxEntrySize int64 // entries larger than this are skipped, unless they're archives
+	MaxEntries   int   // total number of entries read
+	MaxDepth     int   // nesting of the archives read, 1 reads the entries of the archive only
+}
+
+// Entry is a file within an archive
+type Entry struct {
+	Path string // path within the archive, entries of nested archives are separated with Separator
+	Data []byte
+}
+
+// Format returns the format of the archive based on its name, or an empty string if it isn't supported
+func Format(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGz
+	case strings.HasSuffix(name, ".tar.bz2"), strings.HasSuffix(name, ".tbz2"):
+		return FormatTarBz
+	}
+	switch path.Ext(name) {
+	case ".zip", ".jar", ".war", ".ear", ".whl", ".nupkg":
+		return FormatZip
+	case ".tar":
+		return FormatTar
+	case ".gz":
+		return FormatGzip
+	}
+	return ""
+}
+
+// Walk will call fn for every regular file within the archive, in the order they're stored. Nested archives
+// are walked in place of their entry, up to the maximum depth. Reading stops with an error wrapping ErrLimit
+// when the size or number of entries exceeds the limits, the entries read until then have been reported.
+func Walk(filename string, limits Limits, fn func(Entry)) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	w := walker{limits: limits, fn: fn}
+	return w.walk("", path.Base(filename), f, fi.Size(), 1)
+}
+
+// WalkData will call fn for every regular file within the archive held in memory, see Walk
+func WalkData(name string, data []byte, limits Limits, fn func(Entry)) error {
+	w := walker{limits: limits, fn: fn}
+	return w.walk("", path.Base(name), bytes.NewReader(data), int64(len(data)), 1)
+}
+
+type walker struct {
+	fn      func(Entry)
+	limits  Limits
+	size    int64
+	entries int
+}
+
+// walk will read the archive named name, whose entries are reported with the prefix
+func (w *walker) walk(prefix, name string, r io.ReaderAt, size int64, depth int) error {
+	sr := io.NewSectionReader(r, 0, size)
+	switch Format(name) {
+	case FormatZip:
+		return w.walkZip(prefix, r, size, depth)
+	case FormatTar:
+		return w.walkTar(prefix, sr, depth)
+	case FormatTarGz:
+		gz, err := gzip.NewReader(sr)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return w.walkTar(prefix, gz, depth)
+	case FormatTarBz:
+		return w.walkTar(prefix, bzip2.NewReader(sr), depth)
+	case FormatGzip:
+		gz, err := gzip.NewReader(sr)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return w.entry(prefix+strings.TrimSuffix(path.Base(name), path.Ext(name)), gz, depth)
+	}
+	return fmt.Errorf("unsupported archive %s", name)
+}
+
+func (w *walker) walkZip(prefix string, r io.ReaderAt, size int64, depth int) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			log.Log.Debug("Skipping %s%s, %s", prefix, f.Name, err.Error())
+			continue
+		}
+		err = w.entry(prefix+f.Name, rc, depth)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkTar(prefix string, r io.Reader, depth int) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := w.entry(prefix+strings.TrimPrefix(hdr.Name, "./"), tr, depth); err != nil {
+			return err
+		}
+		// the rest of skipped entries is decompressed as well
+		if err := w.skip(tr); err != nil {
+			return err
+		}
+	}
+}
+
+// entry will read the entry at the given path, and walk it if it's an archive itself. Only limit errors
+// are returned, entries that can't be read are skipped.
+func (w *walker) entry(name string, r io.Reader, depth int) error {
+	w.entries++
+	if w.limits.MaxEntries > 0 && w.entries > w.limits.MaxEntries {
+		return fmt.Errorf("%w, more than %d entries", ErrLimit, w.limits.MaxEntries)
+	}
+
+	nested := Format(name) != "" && depth < w.limits.MaxDepth
+	limit := w.limits.MaxEntrySize
+	if nested || limit <= 0 {
+		limit = -1
+	}
+	data, err := w.read(r, limit)
+	switch {
+	case errors.Is(err, ErrLimit):
+		return err
+	case err != nil:
+		log.Log.Debug("Skipping %s, %s", name, err.Error())
+		return nil
+	}
+
+	if nested {
+		err := w.walk(name+Separator, name, bytes.NewReader(data), int64(len(data)), depth+1)
+		if err != nil && !errors.Is(err, ErrLimit) {
+			// not an archive after all, e.g. a file named .gz that isn't compressed
+			log.Log.Debug("Skipping %s, %s", name, err.Error())
+			return nil
+		}
+		return err
+	}
+	w.fn(Entry{Path: name, Data: data})
+	return nil
+}
+
+var errTooLarge = errors.New("entry is too large")
+
+// read will read the entry up to the given size, or the remaining size of the archive when negative.
+// The bytes actually read are counted rather than the sizes declared by the archive, which can't be
+// trusted.
+func (w *walker) read(r io.Reader, limit int64) ([]byte, error) {
+	remaining := int64(-1)
+	if w.limits.MaxSize > 0 {
+		remaining = w.limits.MaxSize - w.size
+	}
+	if limit < 0 || (remaining >= 0 && remaining < limit) {
+		limit = remaining
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if limit < 0 {
+		_, err = buf.ReadFrom(r)
+	} else {
+		_, err = buf.ReadFrom(io.LimitReader(r, limit+1))
+	}
+	w.size += int64(buf.Len())
+	if err != nil {
+		return nil, err
+	}
+	if limit >= 0 && int64(buf.Len()) > limit {
+		if limit == remaining {
+			return nil, w.limitError()
+		}
+		return nil, errTooLarge
+	}
+	if buf.Len() == 0 {
+		return []byte{}, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// skip will read the rest of an entry of a stream, which counts towards the size of the archive as well
+func (w *walker) skip(r io.Reader) error {
+	if w.limits.MaxSize <= 0 {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(r, w.limits.MaxSize-w.size+1))
+	w.size += n
+	if w.size > w.limits.MaxSize {
+		return w.limitError()
+	}
+	return err
+}
+
+func (w *walker) limitError() error {
+	return fmt.Errorf("%w, more than %d bytes extracted", ErrLimit, w.limits.MaxSize)
+}
@@ -0,0 +1,168 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"dist/app.zip":               FormatZip,
+		"lib/commons.JAR":            FormatZip,
+		"requests-2.31.0.whl":        FormatZip,
+		"Newtonsoft.Json.13.0.nupkg": FormatZip,
+		"backup.tar":                 FormatTar,
+		"release.tar.gz":             FormatTarGz,
+		"release.tgz":                FormatTarGz,
+		"release.tar.bz2":            FormatTarBz,
+		"config.json.gz":             FormatGzip,
+		"main.go":                    "",
+		"zip":                        "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, Format(name), name)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	jar := makeZip(t, map[string]string{"application.properties": "db.password=s3cr3t"})
+	tgz := makeTarGz(t, map[string]string{"./config/.env": "TOKEN=abc", "lib/app.jar": string(jar)})
+	gz := makeGzip(t, "{}")
+	dir := t.TempDir()
+	file := filepath.Join(dir, "release.zip")
+	require.NoError(t, os.WriteFile(file, makeZip(t, map[string]string{
+		"README.md":           "readme",
+		"dist/release.tgz":    string(tgz),
+		"dist/config.json.gz": string(gz),
+	}), 0600))
+
+	tests := []struct {
+		name   string
+		limits Limits
+		want   map[string]string
+	}{
+		{"nested", Limits{MaxDepth: 3}, map[string]string{
+			"README.md":                     "readme",
+			"dist/release.tgz!/config/.env": "TOKEN=abc",
+			"dist/release.tgz!/lib/app.jar!/application.properties": "db.password=s3cr3t",
+			"dist/config.json.gz!/config.json":                      "{}",
+		}},
+		{"depth", Limits{MaxDepth: 2}, map[string]string{
+			"README.md":                        "readme",
+			"dist/release.tgz!/config/.env":    "TOKEN=abc",
+			"dist/release.tgz!/lib/app.jar":    string(jar),
+			"dist/config.json.gz!/config.json": "{}",
+		}},
+		{"entry size", Limits{MaxDepth: 3, MaxEntrySize: 8}, map[string]string{
+			"README.md":                        "readme",
+			"dist/config.json.gz!/config.json": "{}",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			err := Walk(file, tt.limits, func(e Entry) {
+				got[e.Path] = string(e.Data)
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			got = make(map[string]string)
+			err = WalkData(file, data, tt.limits, func(e Entry) {
+				got[e.Path] = string(e.Data)
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWalk_limits(t *testing.T) {
+	dir := t.TempDir()
+	bomb := filepath.Join(dir, "bomb.zip")
+	require.NoError(t, os.WriteFile(bomb, makeZip(t, map[string]string{
+		"a.txt": strings.Repeat("0", 1<<20),
+		"b.txt": strings.Repeat("0", 1<<20),
+	}), 0600))
+	fi, err := os.Stat(bomb)
+	require.NoError(t, err)
+	assert.Less(t, fi.Size(), int64(1<<14))
+
+	var got []string
+	err = Walk(bomb, Limits{MaxSize: 1 << 20, MaxDepth: 1}, func(e Entry) {
+		got = append(got, e.Path)
+	})
+	assert.ErrorIs(t, err, ErrLimit)
+	assert.Equal(t, []string{"a.txt"}, got)
+
+	got = nil
+	err = Walk(bomb, Limits{MaxEntries: 1, MaxDepth: 1}, func(e Entry) {
+		got = append(got, e.Path)
+	})
+	assert.ErrorIs(t, err, ErrLimit)
+	assert.Equal(t, []string{"a.txt"}, got)
+}
+
+func TestWalk_invalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "broken.zip")
+	require.NoError(t, os.WriteFile(file, []byte("not a zip file"), 0600))
+	assert.Error(t, Walk(file, Limits{MaxDepth: 1}, func(Entry) {}))
+}
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range sortedKeys(files) {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	require.NoError(t, w.WriteHeader(&tar.Header{Name: "./config/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, name := range sortedKeys(files) {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0600, Size: int64(len(files[name]))}))
+		_, err := w.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func makeGzip(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,198 @@
+// Package extract pulls the text out of documents whose raw content doesn't match well, such as Jupyter
+// notebooks and office documents, so that signatures run on the text a user would see.
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatNotebook = "ipynb" // Jupyter notebooks, the sources and outputs of cells
+	FormatWord     = "docx"  // OOXML documents
+	FormatExcel    = "xlsx"  // OOXML spreadsheets
+	FormatPowerPnt = "pptx"  // OOXML presentations
+	FormatText     = "odt"   // OpenDocument text
+	FormatCalc     = "ods"   // OpenDocument spreadsheets
+)
+
+// Part is a piece of text of a document, e.g. a cell of a notebook or a sheet of a spreadsheet. Parts are
+// scanned as files of their own, so their lines can be found within the document.
+type Part struct {
+	Name string
+	Text string
+}
+
+// errTooLarge is returned when an entry of an office document exceeds the limit
+var errTooLarge = errors.New("document is too large")
+
+// Format returns the format of the document based on its name, or an empty string if it isn't supported
+func Format(name string) string {
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".ipynb", ".docx", ".xlsx", ".pptx", ".odt", ".ods":
+		return ext[1:]
+	}
+	return ""
+}
+
+// Extract will return the parts of the document that hold text, in the order they appear in it. Office
+// documents are zip files, limit bounds the size of every entry decompressed from them when positive.
+func Extract(name string, data []byte, limit int64) ([]Part, error) {
+	format := Format(name)
+	if format == FormatNotebook {
+		return notebook(data)
+	}
+	if format == "" {
+		return nil, fmt.Errorf("unsupported document %s", name)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	d := document{zr: zr, limit: limit}
+	switch format {
+	case FormatWord:
+		return d.word()
+	case FormatExcel:
+		return d.excel()
+	case FormatPowerPnt:
+		return d.powerPoint()
+	default:
+		return d.openDocument()
+	}
+}
+
+// document is an office document, whose entries are read on demand
+type document struct {
+	zr    *zip.Reader
+	limit int64
+}
+
+// files returns the entries matching the pattern, in the order of the numbers in their names so that
+// e.g. slide10.xml comes after slide9.xml
+func (d document) files(pattern string) []*zip.File {
+	var files []*zip.File
+	for _, f := range d.zr.File {
+		if ok, _ := path.Match(pattern, f.Name); ok {
+			files = append(files, f)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return naturalLess(files[i].Name, files[j].Name)
+	})
+	return files
+}
+
+// file returns the entry with the given name
+func (d document) file(name string) *zip.File {
+	for _, f := range d.zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// read will read the entry, up to the limit
+func (d document) read(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	if d.limit <= 0 {
+		return io.ReadAll(rc)
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, d.limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > d.limit {
+		return nil, fmt.Errorf("%w, %s is larger than %d bytes", errTooLarge, f.Name, d.limit)
+	}
+	return data, nil
+}
+
+// partName names a part after its entry, e.g. word/header1.xml is header1
+func partName(name string) string {
+	base := path.Base(name)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// naturalLess compares names with the numbers in them compared by value
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		na, ra := leadingNumber(a)
+		nb, rb := leadingNumber(b)
+		switch {
+		case na != "" && nb != "":
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = ra, rb
+		case a[0] != b[0]:
+			return a[0] < b[0]
+		default:
+			a, b = a[1:], b[1:]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// leadingNumber splits the leading digits, without leading zeros, from the rest of the string
+func leadingNumber(s string) (string, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return "", s
+	}
+	n := strings.TrimLeft(s[:i], "0")
+	if n == "" {
+		n = "0"
+	}
+	return n, s[i:]
+}
+
+// lines builds the text of a part line by line, so that a line can be put at a given line number
+type lines struct {
+	b strings.Builder
+	n int // number of lines written
+}
+
+// add will write the line after the previous one
+func (l *lines) add(line string) {
+	l.at(l.n+1, line)
+}
+
+// at will write the line at the given 1-based line number, or after the previous one if that's already
+// been written
+func (l *lines) at(num int, line string) {
+	for l.n+1 < num {
+		l.b.WriteByte('\n')
+		l.n++
+	}
+	l.b.WriteString(line)
+	l.b.WriteByte('\n')
+	l.n++
+}
+
+// String returns the text, or an empty string if there's no text other than whitespace
+func (l *lines) String() string {
+	if strings.TrimSpace(l.b.String()) == "" {
+		return ""
+	}
+	return l.b.String()
+}
@@ -0,0 +1,106 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ipynb is the nbformat 4 layout of a notebook
+type ipynb struct {
+	Cells []struct {
+		Source  multiline `json:"source"`
+		Outputs []struct {
+			OutputType string               `json:"output_type"`
+			Text       multiline            `json:"text"`      // stream
+			Data       map[string]multiline `json:"data"`      // execute_result, display_data
+			Ename      string               `json:"ename"`     // error
+			Evalue     string               `json:"evalue"`    // error
+			Traceback  []string             `json:"traceback"` // error
+		} `json:"outputs"`
+	} `json:"cells"`
+}
+
+// multiline is a string that notebooks store either whole or as a list of lines. Values of other JSON
+// types, e.g. the application/json data of outputs, are kept as JSON.
+type multiline string
+
+func (m *multiline) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*m = multiline(s)
+		return nil
+	}
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*m = multiline(strings.Join(lines, ""))
+		return nil
+	}
+	*m = multiline(data)
+	return nil
+}
+
+// notebook returns the source of every cell as the part cell-N, and its outputs as cell-N/output-M.
+// Images and other binary outputs are skipped.
+func notebook(data []byte) ([]Part, error) {
+	var nb ipynb
+	if err := json.Unmarshal(data, &nb); err != nil {
+		return nil, err
+	}
+	var parts []Part
+	for i, cell := range nb.Cells {
+		name := fmt.Sprintf("cell-%d", i+1)
+		if strings.TrimSpace(string(cell.Source)) != "" {
+			parts = append(parts, Part{Name: name, Text: string(cell.Source)})
+		}
+		for j, out := range cell.Outputs {
+			var text string
+			switch out.OutputType {
+			case "stream":
+				text = string(out.Text)
+			case "error":
+				text = out.Ename + ": " + out.Evalue + "\n" + stripANSI(strings.Join(out.Traceback, "\n"))
+			default:
+				text = outputData(out.Data)
+			}
+			if strings.TrimSpace(text) != "" {
+				parts = append(parts, Part{Name: fmt.Sprintf("%s/output-%d", name, j+1), Text: text})
+			}
+		}
+	}
+	return parts, nil
+}
+
+// outputData returns the text representations of an output, e.g. text/plain and text/html
+func outputData(data map[string]multiline) string {
+	var types []string
+	for mime := range data {
+		if strings.HasPrefix(mime, "text/") || strings.HasSuffix(mime, "json") {
+			types = append(types, mime)
+		}
+	}
+	sort.Strings(types)
+	var texts []string
+	for _, mime := range types {
+		texts = append(texts, strings.TrimSuffix(string(data[mime]), "\n"))
+	}
+	return strings.Join(texts, "\n")
+}
+
+// stripANSI removes the color escape sequences that tracebacks are printed with
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < '@' || s[j] > '~') {
+				j++
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var odfSpec = textSpec{
+	paragraph: set("p", "h"),
+	tab:       set("tab"),
+	lineBreak: set("line-break"),
+	space:     set("s"),
+}
+
+// openDocument returns the text of the document and the headers and footers of its styles. The tables
+// of spreadsheets are parts of their own, named after the table.
+func (d document) openDocument() ([]Part, error) {
+	f := d.file("content.xml")
+	if f == nil {
+		return nil, errors.New("content.xml not found")
+	}
+	data, err := d.read(f)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(data, []byte("<office:spreadsheet")) {
+		return tables(data)
+	}
+
+	parts, err := d.textParts([]*zip.File{f}, odfSpec)
+	if err != nil {
+		return parts, err
+	}
+	if f := d.file("styles.xml"); f != nil {
+		styles, err := d.textParts([]*zip.File{f}, odfSpec)
+		parts = append(parts, styles...)
+		if err != nil {
+			return parts, err
+		}
+	}
+	return parts, nil
+}
+
+// tables returns the tables of a spreadsheet. Rows are the lines of a table, with the values of the
+// cells separated by tabs.
+func tables(data []byte) ([]Part, error) {
+	var parts []Part
+	var out lines
+	var name string
+	var values []string
+	var cell *strings.Builder
+	row, repeat := 0, 1 // rows of the table read, repetitions of the current row
+	inText := 0         // depth of the paragraphs within a cell
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return parts, nil
+		}
+		if err != nil {
+			return parts, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "table":
+				name, out, row = attr(t, "name"), lines{}, 0
+			case "table-row":
+				values = nil
+				// spreadsheets fill the rest of a sheet with a repeated empty row
+				repeat = 1
+				if n, err := strconv.Atoi(attr(t, "number-rows-repeated")); err == nil && n > 0 {
+					repeat = n
+				}
+			case "table-cell", "covered-table-cell":
+				cell = &strings.Builder{}
+			case "p":
+				if cell != nil && cell.Len() > 0 {
+					cell.WriteByte(' ')
+				}
+				inText++
+			case "s", "tab", "line-break":
+				if cell != nil && inText > 0 {
+					cell.WriteByte(' ')
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "table":
+				if text := out.String(); text != "" {
+					parts = append(parts, Part{Name: name, Text: text})
+				}
+			case "table-row":
+				// repeated rows are written once, the line numbers of the rows after them still match
+				if len(values) > 0 {
+					out.at(row+1, strings.Join(values, "\t"))
+				}
+				row += repeat
+			case "table-cell", "covered-table-cell":
+				if cell != nil && cell.Len() > 0 {
+					values = append(values, cell.String())
+				}
+				cell = nil
+			case "p":
+				if inText > 0 {
+					inText--
+				}
+			}
+		case xml.CharData:
+			if cell != nil && inText > 0 {
+				cell.WriteString(strings.ReplaceAll(string(t), "\n", " "))
+			}
+		}
+	}
+}
+
+// attr returns the value of the attribute with the given local name
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// textSpec describes where the text of an XML part is, by the local names of the elements
+type textSpec struct {
+	paragraph map[string]bool // every paragraph is a line
+	text      map[string]bool // elements whose character data is text, any within a paragraph if nil
+	tab       map[string]bool
+	lineBreak map[string]bool // breaks within a paragraph start a new line
+	space     map[string]bool // the c attribute of ODF text:s is the number of spaces
+}
+
+var wordSpec = textSpec{
+	paragraph: set("p"),
+	// deleted tracked changes and field codes, e.g. hyperlinks, are still in the document
+	text:      set("t", "delText", "instrText"),
+	tab:       set("tab"),
+	lineBreak: set("br", "cr"),
+}
+
+var drawingSpec = textSpec{
+	paragraph: set("p"),
+	text:      set("t"),
+	lineBreak: set("br"),
+}
+
+// word returns the body of the document, followed by its headers, footers, notes and comments
+func (d document) word() ([]Part, error) {
+	var files []*zip.File
+	for _, pattern := range []string{"word/document.xml", "word/header*.xml", "word/footer*.xml",
+		"word/footnotes.xml", "word/endnotes.xml", "word/comments.xml"} {
+		files = append(files, d.files(pattern)...)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("word/document.xml not found")
+	}
+	return d.textParts(files, wordSpec)
+}
+
+// powerPoint returns every slide, followed by the speaker notes
+func (d document) powerPoint() ([]Part, error) {
+	var files []*zip.File
+	for _, pattern := range []string{"ppt/slides/slide*.xml", "ppt/notesSlides/notesSlide*.xml"} {
+		files = append(files, d.files(pattern)...)
+	}
+	return d.textParts(files, drawingSpec)
+}
+
+// textParts returns the parts of the entries that have text
+func (d document) textParts(files []*zip.File, spec textSpec) ([]Part, error) {
+	var parts []Part
+	for _, f := range files {
+		data, err := d.read(f)
+		if err != nil {
+			return parts, err
+		}
+		text, err := xmlText(data, spec)
+		if err != nil {
+			return parts, err
+		}
+		if text != "" {
+			parts = append(parts, Part{Name: partName(f.Name), Text: text})
+		}
+	}
+	return parts, nil
+}
+
+// xmlText returns the paragraphs of an XML part, one per line. Paragraphs nested in others, e.g. notes,
+// are lines of their own.
+func xmlText(data []byte, spec textSpec) (string, error) {
+	var out lines
+	var line strings.Builder
+	depth, inText := 0, 0 // of paragraphs and text elements
+	flush := func() {
+		if line.Len() > 0 {
+			out.add(line.String())
+			line.Reset()
+		}
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var parents []string
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			parent := ""
+			if len(parents) > 0 {
+				parent = parents[len(parents)-1]
+			}
+			parents = append(parents, name)
+			switch {
+			case spec.paragraph[name]:
+				flush()
+				depth++
+			case depth == 0:
+			case spec.text[name]:
+				inText++
+			case spec.tab[name] && parent != "tabs": // w:tabs are the tab stops of a paragraph
+				line.WriteByte('\t')
+			case spec.lineBreak[name]:
+				flush()
+			case spec.space[name]:
+				n := 1
+				if c, err := strconv.Atoi(attr(t, "c")); err == nil && c > 0 && c < 1024 {
+					n = c
+				}
+				line.WriteString(strings.Repeat(" ", n))
+			}
+		case xml.EndElement:
+			name := t.Name.Local
+			if len(parents) > 0 {
+				parents = parents[:len(parents)-1]
+			}
+			switch {
+			case spec.paragraph[name] && depth > 0:
+				flush()
+				depth--
+			case spec.text[name] && inText > 0:
+				inText--
+			}
+		case xml.CharData:
+			if depth > 0 && (spec.text == nil || inText > 0) {
+				line.WriteString(strings.ReplaceAll(string(t), "\n", " "))
+			}
+		}
+	}
+	flush()
+	return out.String(), nil
+}
+
+// richText is a string of a spreadsheet, either plain or made of formatted runs. Phonetic hints are
+// left out.
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r richText) String() string {
+	s := r.T
+	for _, run := range r.Runs {
+		s += run.T
+	}
+	return s
+}
+
+type worksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			T  string    `xml:"t,attr"`
+			V  string    `xml:"v"`
+			Is *richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// excel returns every sheet, named after its entry, e.g. sheet1. Rows are the lines of the sheet, with
+// the values of the cells separated by tabs.
+func (d document) excel() ([]Part, error) {
+	var shared []string
+	if f := d.file("xl/sharedStrings.xml"); f != nil {
+		data, err := d.read(f)
+		if err != nil {
+			return nil, err
+		}
+		var sst struct {
+			Items []richText `xml:"si"`
+		}
+		if err := xml.Unmarshal(data, &sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			shared = append(shared, si.String())
+		}
+	}
+
+	var parts []Part
+	for _, f := range d.files("xl/worksheets/sheet*.xml") {
+		data, err := d.read(f)
+		if err != nil {
+			return parts, err
+		}
+		var ws worksheet
+		if err := xml.Unmarshal(data, &ws); err != nil {
+			return parts, err
+		}
+		var out lines
+		for _, row := range ws.Rows {
+			var values []string
+			for _, c := range row.Cells {
+				v := c.V
+				switch {
+				case c.T == "s":
+					if i, err := strconv.Atoi(v); err == nil && i >= 0 && i < len(shared) {
+						v = shared[i]
+					}
+				case c.T == "inlineStr" && c.Is != nil:
+					v = c.Is.String()
+				}
+				if v != "" {
+					values = append(values, strings.ReplaceAll(v, "\n", " "))
+				}
+			}
+			if len(values) > 0 {
+				out.at(row.R, strings.Join(values, "\t"))
+			}
+		}
+		if text := out.String(); text != "" {
+			parts = append(parts, Part{Name: partName(f.Name), Text: text})
+		}
+	}
+	return parts, nil
+}
+
+func set(names ...string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+	return m
+}
@@ -3,16 +3,28 @@ package finding
 import (
 	"encoding/csv"
 	"os"
+	"strings"
 )
 
 func getCSVHeader() []string {
 	return []string{
 		"FilePath",
 		"Line Number",
+		"End Line Number",
+		"Column Number",
 		"Action",
 		"Description",
 		"SignatureID",
+		"Severity",
+		"Category",
+		"Tags",
+		"Verification",
 		"Finding List",
+		"Encoding",
+		"Encoded",
+		"Key",
+		"Fingerprint",
+		"Layer",
 		"Repo Owner",
 		"Repo Name",
 		"Commit Hash",
@@ -29,10 +41,21 @@ func (f *Finding) toCSV() []string {
 	return []string{
 		f.FilePath,
 		f.LineNumber,
+		f.EndLineNumber,
+		f.ColumnNumber,
 		f.Action,
 		f.Description,
 		f.SignatureID,
+		f.Severity,
+		f.Category,
+		strings.Join(f.Tags, ";"),
+		f.Verification,
 		f.Content,
+		f.Encoding,
+		f.Encoded,
+		f.KeyInfo,
+		f.Fingerprint,
+		f.Layer,
 		f.RepositoryOwner,
 		f.RepositoryName,
 		f.CommitHash,
@@ -8,7 +8,7 @@ import (
 
 func Test_getCSVHeader(t *testing.T) {
 	got := getCSVHeader()
-	want := []string{"FilePath", "Line Number", "Action", "Description", "SignatureID", "Finding List", "Repo Owner", "Repo Name", "Commit Hash", "Commit Message", "Commit Author", "File URL", "Secret ID", "App Version", "Signatures Version"}
+	want := []string{"FilePath", "Line Number", "End Line Number", "Column Number", "Action", "Description", "SignatureID", "Severity", "Category", "Tags", "Verification", "Finding List", "Encoding", "Encoded", "Key", "Fingerprint", "Layer", "Repo Owner", "Repo Name", "Commit Hash", "Commit Message", "Commit Author", "File URL", "Secret ID", "App Version", "Signatures Version"}
 	assert.Equal(t, want, got)
 }
 
@@ -23,22 +23,33 @@ func makeTestFinding() *Finding {
 	return &Finding{
 		"f.Action",
 		"f.AppVersion",
+		"f.Category",
+		"f.ColumnNumber",
 		"f.Content",
 		"f.CommitAuthor",
 		"f.CommitHash",
 		"f.CommitMessage",
 		"f.CommitURL",
 		"f.Description",
+		"f.Encoded",
+		"f.Encoding",
+		"f.EndLineNumber",
 		"f.FilePath",
 		"f.FileURL",
+		"f.Fingerprint",
 		"f.Hash",
+		"f.KeyInfo",
+		"f.Layer",
 		"f.LineNumber",
 		"f.RepositoryName",
 		"f.RepositoryOwner",
 		"f.RepositoryURL",
+		"f.Severity",
 		"f.SignatureID",
 		"f.SignatureVersion",
 		"f.SecretID",
+		[]string{"f.Tag1", "f.Tag2"},
+		"f.Verification",
 	}
 }
 
@@ -46,10 +57,21 @@ func makeTestFindingStringSlice() []string {
 	return []string{
 		"f.FilePath",
 		"f.LineNumber",
+		"f.EndLineNumber",
+		"f.ColumnNumber",
 		"f.Action",
 		"f.Description",
 		"f.SignatureID",
+		"f.Severity",
+		"f.Category",
+		"f.Tag1;f.Tag2",
+		"f.Verification",
 		"f.Content",
+		"f.Encoding",
+		"f.Encoded",
+		"f.KeyInfo",
+		"f.Fingerprint",
+		"f.Layer",
 		"f.RepositoryOwner",
 		"f.RepositoryName",
 		"f.CommitHash",
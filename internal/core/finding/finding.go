@@ -6,8 +6,12 @@ import (
 	"strings"
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/azure"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/bitbucket"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitea"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/github"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitlab"
+	"github.com/rumenvasilev/rvsecret/internal/core/verify"
 	"github.com/rumenvasilev/rvsecret/internal/log"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
 	"github.com/rumenvasilev/rvsecret/internal/util"
@@ -17,51 +21,69 @@ import (
 type Finding struct {
 	Action           string
 	AppVersion       string
+	Category         string
+	ColumnNumber     string
 	Content          string
 	CommitAuthor     string
 	CommitHash       string
 	CommitMessage    string
 	CommitURL        string
 	Description      string
+	Encoded          string // the encoded blob the secret was decoded from
+	Encoding         string // e.g. base64, empty if the secret wasn't encoded
+	EndLineNumber    string // the last line of secrets spanning several lines, e.g. private keys
 	FilePath         string
 	FileURL          string
+	Fingerprint      string // the SHA256 fingerprint of the public key of private keys
 	Hash             string
+	KeyInfo          string // the type, format and encryption of private keys
+	Layer            string // the digest of the image layer the file is in
 	LineNumber       string
 	RepositoryName   string
 	RepositoryOwner  string
 	RepositoryURL    string
+	Severity         string
 	SignatureID      string
 	SignatureVersion string
 	SecretID         string
+	Tags             []string
+	Verification     string // verified, invalid or unknown when the secret was checked
 }
 
-// Initialize will set the urls and create an ID for inclusion within the finding
-func (f *Finding) Initialize(scanType api.ScanType, gheURL string) error {
+// Initialize will set the urls and create an ID for inclusion within the finding. The server URL is the
+// address of self-hosted git servers, e.g. GitHub Enterprise or GitLab.
+func (f *Finding) Initialize(scanType api.ScanType, serverURL string) error {
 	if f == nil {
 		return errors.New("finding is uninitialized")
 	}
-	f.setupUrls(scanType, gheURL)
+	f.setupUrls(scanType, serverURL)
 	return nil
 }
 
 // setupUrls will set the urls used to search through either github or gitlab for inclusion in the finding data
-func (f *Finding) setupUrls(scanType api.ScanType, gheURL string) {
+func (f *Finding) setupUrls(scanType api.ScanType, serverURL string) {
 	var baseURL string
 	switch scanType {
 	case api.Github, api.GithubEnterprise:
 		baseURL = github.Address
 		if scanType == api.GithubEnterprise {
-			baseURL = gheURL
+			baseURL = serverURL
 		}
 		f.RepositoryURL = fmt.Sprintf("%s/%s/%s", baseURL, f.RepositoryOwner, f.RepositoryName)
 		f.FileURL = fmt.Sprintf("%s/blob/%s/%s", f.RepositoryURL, f.CommitHash, f.FilePath)
 		f.CommitURL = fmt.Sprintf("%s/commit/%s", f.RepositoryURL, f.CommitHash)
 	case api.Gitlab:
-		baseURL = gitlab.Address
+		baseURL = gitlab.WebURL(serverURL)
 		results := util.CleanURLSpaces(f.RepositoryOwner, f.RepositoryName)
 		f.RepositoryURL = fmt.Sprintf("%s/%s/%s", baseURL, results[0], results[1])
 		f.FileURL = fmt.Sprintf("%s/blob/%s/%s", f.RepositoryURL, f.CommitHash, f.FilePath)
 		f.CommitURL = fmt.Sprintf("%s/commit/%s", f.RepositoryURL, f.CommitHash)
+	case api.Bitbucket:
+		f.RepositoryURL, f.FileURL, f.CommitURL = bitbucket.Links(serverURL, f.RepositoryOwner, f.RepositoryName, f.CommitHash, f.FilePath)
+	case api.AzureDevOps:
+		f.RepositoryURL, f.FileURL, f.CommitURL = azure.Links(serverURL, f.RepositoryOwner, f.RepositoryName, f.CommitHash, f.FilePath)
+	case api.Gitea:
+		f.RepositoryURL, f.FileURL, f.CommitURL = gitea.Links(serverURL, f.RepositoryOwner, f.RepositoryName, f.CommitHash, f.FilePath)
 	}
 }
 
@@ -70,9 +92,42 @@ func (f *Finding) RealtimeOutput(cfg config.Global) {
 	if !cfg.Silent && !cfg.CSVOutput && !cfg.JSONOutput {
 		log.Warn(" %s", strings.ToUpper(f.Description))
 		log.Info("  SignatureID..........: %s", f.SignatureID)
+		log.Info("  Severity.............: %s", f.Severity)
+		if f.Verification == verify.StatusVerified {
+			log.Important("  Verification.........: %s, the credential is live!", f.Verification)
+		} else if f.Verification != "" {
+			log.Info("  Verification.........: %s", f.Verification)
+		}
+		if f.Category != "" {
+			log.Info("  Category.............: %s", f.Category)
+		}
+		if len(f.Tags) > 0 {
+			log.Info("  Tags.................: %s", strings.Join(f.Tags, ", "))
+		}
 		log.Info("  Repo.................: %s", f.RepositoryName)
 		log.Info("  File Path............: %s", f.FilePath)
+		if f.Layer != "" {
+			log.Info("  Layer................: %s", f.Layer)
+		}
 		log.Info("  Line Number..........: %s", f.LineNumber)
+		if f.EndLineNumber != "" {
+			log.Info("  End Line Number......: %s", f.EndLineNumber)
+		}
+		if f.ColumnNumber != "" {
+			log.Info("  Column Number........: %s", f.ColumnNumber)
+		}
+		if f.Encoding != "" {
+			log.Info("  Encoding.............: %s", f.Encoding)
+			if f.Encoded != "" {
+				log.Info("  Encoded..............: %s", util.TruncateString(f.Encoded, 100))
+			}
+		}
+		if f.KeyInfo != "" {
+			log.Info("  Key..................: %s", f.KeyInfo)
+		}
+		if f.Fingerprint != "" {
+			log.Info("  Fingerprint..........: %s", f.Fingerprint)
+		}
 		log.Info("  Message..............: %s", util.TruncateString(f.CommitMessage, 100))
 		log.Info("  Commit Hash..........: %s", util.TruncateString(f.CommitHash, 100))
 		log.Info("  Author...............: %s", f.CommitAuthor)
@@ -9,9 +9,9 @@ import (
 
 func TestFinding_Initialize(t *testing.T) {
 	type args struct {
-		finding  *Finding
-		scanType api.ScanType
-		gheURL   string
+		finding   *Finding
+		scanType  api.ScanType
+		serverURL string
 	}
 	tests := []struct {
 		name    string
@@ -21,14 +21,19 @@ func TestFinding_Initialize(t *testing.T) {
 	}{
 		{"github", args{&Finding{}, api.Github, "doesnt-matter"}, &Finding{CommitURL: "https://github.com///commit/", FileURL: "https://github.com///blob//", RepositoryURL: "https://github.com//"}, false},
 		{"github_enterprise", args{&Finding{}, api.GithubEnterprise, "gitfake.enterprise"}, &Finding{CommitURL: "gitfake.enterprise///commit/", FileURL: "gitfake.enterprise///blob//", RepositoryURL: "gitfake.enterprise//"}, false},
-		{"gitlab", args{&Finding{}, api.Gitlab, "doesnt-matter"}, &Finding{CommitURL: "https://gitlab.com///commit/", FileURL: "https://gitlab.com///blob//", RepositoryURL: "https://gitlab.com//"}, false},
+		{"gitlab", args{&Finding{}, api.Gitlab, ""}, &Finding{CommitURL: "https://gitlab.com///commit/", FileURL: "https://gitlab.com///blob//", RepositoryURL: "https://gitlab.com//"}, false},
+		{"gitlab self-hosted", args{&Finding{RepositoryOwner: "platform", RepositoryName: "api"}, api.Gitlab, "https://gitlab.example.com/"}, &Finding{RepositoryOwner: "platform", RepositoryName: "api", CommitURL: "https://gitlab.example.com/platform/api/commit/", FileURL: "https://gitlab.example.com/platform/api/blob//", RepositoryURL: "https://gitlab.example.com/platform/api"}, false},
+		{"bitbucket", args{&Finding{RepositoryOwner: "acme", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml"}, api.Bitbucket, ""}, &Finding{RepositoryOwner: "acme", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml", CommitURL: "https://bitbucket.org/acme/api/commits/abc", FileURL: "https://bitbucket.org/acme/api/src/abc/app.yaml", RepositoryURL: "https://bitbucket.org/acme/api"}, false},
+		{"bitbucket server", args{&Finding{RepositoryOwner: "PLAT", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml"}, api.Bitbucket, "https://bb.example.com"}, &Finding{RepositoryOwner: "PLAT", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml", CommitURL: "https://bb.example.com/projects/PLAT/repos/api/commits/abc", FileURL: "https://bb.example.com/projects/PLAT/repos/api/browse/app.yaml?at=abc", RepositoryURL: "https://bb.example.com/projects/PLAT/repos/api"}, false},
+		{"azure devops", args{&Finding{RepositoryOwner: "contoso/Web", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml"}, api.AzureDevOps, ""}, &Finding{RepositoryOwner: "contoso/Web", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml", CommitURL: "https://dev.azure.com/contoso/Web/_git/api/commit/abc", FileURL: "https://dev.azure.com/contoso/Web/_git/api?path=/app.yaml&version=GCabc", RepositoryURL: "https://dev.azure.com/contoso/Web/_git/api"}, false},
+		{"gitea", args{&Finding{RepositoryOwner: "infra", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml"}, api.Gitea, "https://gitea.example.com"}, &Finding{RepositoryOwner: "infra", RepositoryName: "api", CommitHash: "abc", FilePath: "app.yaml", CommitURL: "https://gitea.example.com/infra/api/commit/abc", FileURL: "https://gitea.example.com/infra/api/src/commit/abc/app.yaml", RepositoryURL: "https://gitea.example.com/infra/api"}, false},
 		{"unsupported", args{&Finding{}, api.Unknown, ""}, &Finding{}, false},
 		{"uninitialized", args{nil, "random string", ""}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			f := tt.args.finding
-			err := f.Initialize(tt.args.scanType, tt.args.gheURL)
+			err := f.Initialize(tt.args.scanType, tt.args.serverURL)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -7,9 +7,15 @@ import (
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
 	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/gitauth"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/azure"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/bitbucket"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitea"
+	"github.com/rumenvasilev/rvsecret/internal/core/remote"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
@@ -60,6 +66,7 @@ func cloneRepository(cfg *config.Config, statsIncrementer func(), repo _coreapi.
 func cloneRepositoryWrapper(cfg *config.Config, repo _coreapi.Repository) (*git.Repository, string, error) {
 	var cloneConfig CloneConfiguration
 	var auth = http.BasicAuth{}
+	var token transport.AuthMethod = &auth
 	switch cfg.Global.ScanType {
 	case api.Github, api.GithubEnterprise:
 		cloneConfig = CloneConfiguration{
@@ -81,6 +88,42 @@ func cloneRepositoryWrapper(cfg *config.Config, repo _coreapi.Repository) (*git.
 		}
 		auth.Username = "oauth2"
 		auth.Password = cfg.Gitlab.APIToken
+	case api.Bitbucket:
+		cloneConfig = CloneConfiguration{
+			URL:        repo.CloneURL,
+			Branch:     repo.DefaultBranch,
+			Depth:      cfg.Global.CommitDepth,
+			InMemClone: cfg.Global.InMemClone,
+		}
+		auth.Username = bitbucket.CloneUsername(cfg.Bitbucket.Username)
+		auth.Password = cfg.Bitbucket.APIToken
+	case api.AzureDevOps:
+		cloneConfig = CloneConfiguration{
+			URL:        repo.CloneURL,
+			Branch:     repo.DefaultBranch,
+			Depth:      cfg.Global.CommitDepth,
+			InMemClone: cfg.Global.InMemClone,
+		}
+		auth.Username = azure.CloneUsername
+		auth.Password = cfg.AzureDevOps.APIToken
+	case api.Gitea:
+		cloneConfig = CloneConfiguration{
+			URL:        repo.CloneURL,
+			Branch:     repo.DefaultBranch,
+			Depth:      cfg.Global.CommitDepth,
+			InMemClone: cfg.Global.InMemClone,
+		}
+		auth.Username = cfg.Gitea.APIToken
+		auth.Password = gitea.ClonePassword
+	case api.GitRemotes:
+		cloneConfig = CloneConfiguration{
+			URL:        repo.CloneURL,
+			Branch:     repo.DefaultBranch,
+			Depth:      cfg.Global.CommitDepth,
+			InMemClone: cfg.Global.InMemClone,
+		}
+		// without a credential for the host, the clone uses the ones in the URL
+		token = remote.BasicAuth(cfg.Remotes, repo.CloneURL)
 	case api.LocalGit:
 		cloneConfig = CloneConfiguration{
 			URL:        repo.CloneURL,
@@ -91,27 +134,38 @@ func cloneRepositoryWrapper(cfg *config.Config, repo _coreapi.Repository) (*git.
 	default:
 		return nil, "", fmt.Errorf("unsupported scantype")
 	}
-	return CloneRepositoryGeneric(cloneConfig, &auth)
+	if cfg.Clone.SSH && repo.SSHURL != "" {
+		cloneConfig.URL = repo.SSHURL
+	}
+	method, err := gitauth.Method(cfg.Clone, cloneConfig.URL, token)
+	if err != nil {
+		return nil, "", err
+	}
+	// the remotes and the Bitbucket Server repositories don't know their default branch
+	if (cfg.Global.ScanType == api.GitRemotes || cfg.Global.ScanType == api.Bitbucket) && cloneConfig.Branch == "" {
+		cloneConfig.Branch, err = remote.DefaultBranch(cloneConfig.URL, method)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return CloneRepositoryGeneric(cloneConfig, method)
 }
 
 // cloneRepositoryGeneric will create either an in memory clone of a given repository or clone to a temp dir.
-func CloneRepositoryGeneric(config CloneConfiguration, auth *http.BasicAuth) (repo *git.Repository, dir string, err error) {
+func CloneRepositoryGeneric(config CloneConfiguration, auth transport.AuthMethod) (repo *git.Repository, dir string, err error) {
 	ref := plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", config.Branch))
 	if config.Tag {
 		ref = plumbing.ReferenceName(fmt.Sprintf("refs/tags/%s", config.Branch))
 	}
 	cloneOptions := &git.CloneOptions{
 		URL:           config.URL,
+		Auth:          auth,
 		Depth:         config.Depth,
 		ReferenceName: ref,
 		SingleBranch:  true,
 		Tags:          config.TagMode,
 	}
 
-	if auth != nil {
-		cloneOptions.Auth = auth
-	}
-
 	if config.TagMode == git.InvalidTagMode {
 		cloneOptions.Tags = git.NoTags
 	}
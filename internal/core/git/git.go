@@ -2,6 +2,8 @@ package git
 
 import (
 	"errors"
+	"fmt"
+	"io"
 
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -93,6 +95,31 @@ func GetChangePath(change *object.Change) string {
 
 }
 
+// GetChangeBlob returns the content of the file of the change as of the commit, or before it for deleted
+// files. Files larger than maxSize bytes aren't read.
+func GetChangeBlob(change *object.Change, maxSize int64) ([]byte, error) {
+	from, to, err := change.Files()
+	if err != nil {
+		return nil, err
+	}
+	file := to
+	if file == nil {
+		file = from
+	}
+	if file == nil {
+		return nil, errors.New("the change has no file")
+	}
+	if maxSize > 0 && file.Size > maxSize {
+		return nil, fmt.Errorf("the file has %d bytes, more than %d", file.Size, maxSize)
+	}
+	r, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
 // GetChangeContent will get the contents of a git change or patch.
 func GetChangeContent(change *object.Change) (result string, contentError error) {
 	//temporary response to:  https://github.com/sergi/go-diff/issues/89
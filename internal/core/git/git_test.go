@@ -0,0 +1,55 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestGetChangeBlob(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	commit := func(msg string, change func()) *object.Commit {
+		change()
+		_, err := wt.Add(".")
+		require.NoError(t, err)
+		hash, err := wt.Commit(msg, &git.CommitOptions{All: true, Author: &object.Signature{Name: "jane", Email: "jane@example.com", When: time.Now()}})
+		require.NoError(t, err)
+		c, err := repo.CommitObject(hash)
+		require.NoError(t, err)
+		return c
+	}
+	write := func(content string) func() {
+		return func() { require.NoError(t, os.WriteFile(filepath.Join(dir, "app.jar"), []byte(content), 0600)) }
+	}
+	commit("add", write("one"))
+	modified := commit("modify", write("two"))
+	deleted := commit("delete", func() { require.NoError(t, os.Remove(filepath.Join(dir, "app.jar"))) })
+
+	changes, err := GetChanges(modified, repo)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	data, err := GetChangeBlob(changes[0], 0)
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+
+	_, err = GetChangeBlob(changes[0], 2)
+	assert.EqualError(t, err, "the file has 3 bytes, more than 2")
+
+	// deleted files are read as they were before the commit
+	changes, err = GetChanges(deleted, repo)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	data, err = GetChangeBlob(changes[0], 0)
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+}
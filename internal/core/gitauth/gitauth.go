@@ -0,0 +1,101 @@
+// Package gitauth chooses how to authenticate the clone of a repository: with an ssh key or the ssh agent for
+// ssh addresses, and for http with a git credential helper or a netrc file instead of the token of the provider.
+package gitauth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+)
+
+// sshUser is the user of ssh addresses without one, which is the one of all the hosting services
+const sshUser = "git"
+
+// Method returns the auth method for cloning from the address. The token auth of the provider is used for
+// http addresses, unless a credential helper or a netrc file are set. Then it's never used, so that the
+// token doesn't go into clones, and addresses they have no credentials for are cloned anonymously.
+func Method(cfg config.Clone, rawURL string, token transport.AuthMethod) (transport.AuthMethod, error) {
+	ep, err := transport.NewEndpoint(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch ep.Protocol {
+	case "ssh":
+		return sshMethod(cfg, ep)
+	case "http", "https":
+		if !cfg.CredentialHelper && cfg.Netrc == "" {
+			return token, nil
+		}
+		return httpMethod(cfg, ep)
+	}
+	return token, nil
+}
+
+// sshMethod authenticates with the key file, or with the ssh agent when there is none. The host key is
+// verified against the known hosts file, ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts by default.
+func sshMethod(cfg config.Clone, ep *transport.Endpoint) (transport.AuthMethod, error) {
+	user := ep.User
+	if user == "" {
+		user = sshUser
+	}
+	var hostKeyCallback ssh.HostKeyCallback
+	if cfg.KnownHosts != "" {
+		file, err := homedir.Expand(cfg.KnownHosts)
+		if err != nil {
+			return nil, err
+		}
+		hostKeyCallback, err = gitssh.NewKnownHostsCallback(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the known hosts file %s, %w", cfg.KnownHosts, err)
+		}
+	}
+
+	if cfg.SSHKey == "" {
+		auth, err := gitssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, fmt.Errorf("cannot use the ssh agent, set an ssh key file instead, %w", err)
+		}
+		auth.HostKeyCallback = hostKeyCallback
+		return auth, nil
+	}
+	signer, err := readKey(cfg.SSHKey, cfg.SSHKeyPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	auth := &gitssh.PublicKeys{User: user, Signer: signer}
+	auth.HostKeyCallback = hostKeyCallback
+	return auth, nil
+}
+
+// readKey reads the private key file, in the OpenSSH or a PEM format, encrypted with the passphrase when
+// one is given
+func readKey(file, passphrase string) (ssh.Signer, error) {
+	path, err := homedir.Expand(file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the ssh key, %w", err)
+	}
+	var signer ssh.Signer
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(data)
+	}
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, fmt.Errorf("the ssh key %s is encrypted, set its passphrase or add it to the ssh agent", file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the ssh key %s, %w", file, err)
+	}
+	return signer, nil
+}
@@ -0,0 +1,154 @@
+package gitauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+)
+
+func TestMethod(t *testing.T) {
+	dir := t.TempDir()
+	netrc := filepath.Join(dir, "netrc")
+	require.NoError(t, os.WriteFile(netrc, []byte("machine git.example.com login jane password one\n"), 0600))
+	token := &http.BasicAuth{Username: "x-access-token", Password: "token"}
+
+	tests := []struct {
+		name string
+		cfg  config.Clone
+		url  string
+		want transport.AuthMethod
+	}{
+		{"token", config.Clone{}, "https://git.example.com/team/app.git", token},
+		{"netrc", config.Clone{Netrc: netrc}, "https://git.example.com/team/app.git", &http.BasicAuth{Username: "jane", Password: "one"}},
+		{"netrc without the host", config.Clone{Netrc: netrc}, "https://other.example.com/team/app.git", nil},
+		{"file", config.Clone{Netrc: netrc}, "file:///srv/git/app.git", token},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Method(tt.cfg, tt.url, token)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Method(config.Clone{Netrc: filepath.Join(dir, "missing")}, "https://git.example.com/team/app.git", token)
+	assert.Error(t, err)
+}
+
+func TestMethodSSH(t *testing.T) {
+	dir := t.TempDir()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(key, "")
+	require.NoError(t, err)
+	plain := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(plain, pem.EncodeToMemory(block), 0600))
+	block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("secret"))
+	require.NoError(t, err)
+	encrypted := filepath.Join(dir, "id_encrypted")
+	require.NoError(t, os.WriteFile(encrypted, pem.EncodeToMemory(block), 0600))
+	knownHosts := filepath.Join(dir, "known_hosts")
+	require.NoError(t, os.WriteFile(knownHosts, nil, 0600))
+
+	tests := []struct {
+		name, url, user, wantErr string
+		cfg                      config.Clone
+	}{
+		{"scp-like", "git@git.example.com:team/app.git", "git", "", config.Clone{SSHKey: plain}},
+		{"default user", "ssh://git.example.com/team/app.git", "git", "", config.Clone{SSHKey: plain}},
+		{"other user", "ssh://deploy@git.example.com:2222/team/app.git", "deploy", "", config.Clone{SSHKey: plain}},
+		{"passphrase", "git@git.example.com:team/app.git", "git", "", config.Clone{SSHKey: encrypted, SSHKeyPassphrase: "secret"}},
+		{"known hosts", "git@git.example.com:team/app.git", "git", "", config.Clone{SSHKey: plain, KnownHosts: knownHosts}},
+		{"missing passphrase", "git@git.example.com:team/app.git", "", "the ssh key " + encrypted + " is encrypted, set its passphrase or add it to the ssh agent", config.Clone{SSHKey: encrypted}},
+		{"wrong passphrase", "git@git.example.com:team/app.git", "", "cannot parse the ssh key " + encrypted, config.Clone{SSHKey: encrypted, SSHKeyPassphrase: "wrong"}},
+		{"missing key", "git@git.example.com:team/app.git", "", "cannot read the ssh key", config.Clone{SSHKey: filepath.Join(dir, "missing")}},
+		{"missing known hosts", "git@git.example.com:team/app.git", "", "cannot read the known hosts file", config.Clone{SSHKey: plain, KnownHosts: filepath.Join(dir, "missing")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Method(tt.cfg, tt.url, &http.BasicAuth{Password: "token"})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.True(t, strings.HasPrefix(err.Error(), tt.wantErr), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			auth, ok := got.(*gitssh.PublicKeys)
+			require.True(t, ok)
+			assert.Equal(t, tt.user, auth.User)
+			assert.Equal(t, tt.cfg.KnownHosts != "", auth.HostKeyCallback != nil)
+		})
+	}
+}
+
+func TestMethodSSHAgent(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	_, err := Method(config.Clone{}, "git@git.example.com:team/app.git", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot use the ssh agent, set an ssh key file instead")
+}
+
+func TestParseNetrc(t *testing.T) {
+	machines, err := parseNetrc(strings.NewReader(`machine git.example.com
+	login jane
+	password one
+macdef init
+machine macro.example.com login nobody password none
+
+machine mirror.example.com login ci password two account build
+default login anonymous password guest
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []netrcMachine{
+		{name: "git.example.com", login: "jane", password: "one"},
+		{name: "mirror.example.com", login: "ci", password: "two"},
+		{login: "anonymous", password: "guest", isDefault: true},
+	}, machines)
+}
+
+func TestNetrcAuth(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "netrc")
+	require.NoError(t, os.WriteFile(file, []byte("machine git.example.com login jane password one\ndefault login anonymous password guest\n"), 0600))
+
+	auth, err := netrcAuth(file, "GIT.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, &http.BasicAuth{Username: "jane", Password: "one"}, auth)
+
+	auth, err = netrcAuth(file, "other.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, &http.BasicAuth{Username: "anonymous", Password: "guest"}, auth)
+}
+
+func TestCredentialFill(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	gitconfig := filepath.Join(dir, "gitconfig")
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", gitconfig)
+
+	require.NoError(t, os.WriteFile(gitconfig, nil, 0600))
+	auth, err := credentialFill("https", "git.example.com", "team/app.git")
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+
+	require.NoError(t, os.WriteFile(gitconfig, []byte("[credential]\n\thelper = \"!f() { echo username=ci; echo password=two; }; f\"\n"), 0600))
+	got, err := Method(config.Clone{CredentialHelper: true}, "https://git.example.com/team/app.git", &http.BasicAuth{Password: "token"})
+	require.NoError(t, err)
+	assert.Equal(t, &http.BasicAuth{Username: "ci", Password: "two"}, got)
+}
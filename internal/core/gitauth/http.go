@@ -0,0 +1,169 @@
+package gitauth
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+)
+
+// httpMethod asks the credential helpers of git for the credentials of the address, then looks them up in
+// the netrc file. Without any the clone is anonymous.
+func httpMethod(cfg config.Clone, ep *transport.Endpoint) (transport.AuthMethod, error) {
+	host := ep.Host
+	if ep.Port != 0 && ep.Port != defaultPort(ep.Protocol) {
+		host = fmt.Sprintf("%s:%d", ep.Host, ep.Port)
+	}
+	if cfg.CredentialHelper {
+		auth, err := credentialFill(ep.Protocol, host, strings.TrimPrefix(ep.Path, "/"))
+		if err != nil {
+			return nil, err
+		}
+		if auth != nil {
+			return auth, nil
+		}
+	}
+	if cfg.Netrc != "" {
+		auth, err := netrcAuth(cfg.Netrc, ep.Host)
+		if err != nil {
+			return nil, err
+		}
+		if auth != nil {
+			return auth, nil
+		}
+	}
+	return nil, nil
+}
+
+func defaultPort(protocol string) int {
+	if protocol == "http" {
+		return 80
+	}
+	return 443
+}
+
+// credentialFill runs git credential fill, which asks the configured credential helpers. Prompting for
+// the credentials is disabled, and there are none when the helpers don't have them.
+func credentialFill(protocol, host, path string) (*http.BasicAuth, error) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\npath=%s\n\n", protocol, host, path))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	out, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// git exits with an error when it would have to prompt
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot run git credential fill, %w", err)
+	}
+	auth := &http.BasicAuth{}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		key, value, _ := strings.Cut(s.Text(), "=")
+		switch key {
+		case "username":
+			auth.Username = value
+		case "password":
+			auth.Password = value
+		}
+	}
+	if auth.Password == "" {
+		return nil, nil
+	}
+	return auth, nil
+}
+
+// netrcAuth returns the login and password of the host in the netrc file, or of the default entry
+func netrcAuth(file, host string) (*http.BasicAuth, error) {
+	path, err := homedir.Expand(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the netrc file, %w", err)
+	}
+	defer f.Close()
+	machines, err := parseNetrc(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the netrc file, %w", err)
+	}
+	var def *http.BasicAuth
+	for _, m := range machines {
+		auth := &http.BasicAuth{Username: m.login, Password: m.password}
+		if m.isDefault {
+			if def == nil {
+				def = auth
+			}
+			continue
+		}
+		if strings.EqualFold(m.name, host) {
+			return auth, nil
+		}
+	}
+	return def, nil
+}
+
+type netrcMachine struct {
+	name, login, password string
+	isDefault             bool
+}
+
+// parseNetrc reads the machine and default entries of a netrc file. The tokens are separated by any white
+// space, and the macros, which run to the next empty line, are skipped.
+func parseNetrc(r io.Reader) ([]netrcMachine, error) {
+	var tokens []string
+	inMacro := false
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if inMacro {
+			inMacro = strings.TrimSpace(s.Text()) != ""
+			continue
+		}
+		fields := strings.Fields(s.Text())
+		for i, f := range fields {
+			if f == "macdef" {
+				fields, inMacro = fields[:i], true
+				break
+			}
+		}
+		tokens = append(tokens, fields...)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	var machines []netrcMachine
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine", "default":
+			m := netrcMachine{isDefault: tokens[i] == "default"}
+			if !m.isDefault && i+1 < len(tokens) {
+				i++
+				m.name = tokens[i]
+			}
+			machines = append(machines, m)
+		case "login", "password", "account":
+			if i+1 == len(tokens) || len(machines) == 0 {
+				i++
+				continue
+			}
+			m := &machines[len(machines)-1]
+			if tokens[i] == "login" {
+				m.login = tokens[i+1]
+			} else if tokens[i] == "password" {
+				m.password = tokens[i+1]
+			}
+			i++
+		}
+	}
+	return machines, nil
+}
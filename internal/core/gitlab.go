@@ -28,8 +28,9 @@ func GatherTargets(sess *session.Session) {
 	//case "github":
 	//	targets = sess.GithubTargets
 	//case "gitlab":
-	targets := sess.Config.Gitlab.Targets
+	//	targets = sess.Config.Gitlab.Targets
 	//}
+	targets := scanTargets(sess.Config)
 
 	//var target *Owner
 
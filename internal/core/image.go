@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/image"
+	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+)
+
+// The image config is scanned as two files, the env vars as a dotenv file so that key/value signatures
+// match them
+const (
+	imageEnvPath     = "Config.Env"
+	imageHistoryPath = "History"
+)
+
+// actionRemoved is the action of findings in files that a later layer removed, as for git changes
+const actionRemoved = "Delete"
+
+// imageFile is a file of a layer waiting for a worker
+type imageFile struct {
+	mf  matchfile.MatchFile
+	src source
+}
+
+// AnalyzeImage will scan the config of the image and the files of every layer, reported with the digest
+// of their layer. Files that a later layer removed or replaced are scanned as well, since they can still
+// be extracted from the image. The layers are read in order, and their files are scanned by as many workers
+// as the configured threads, so only the files waiting for a worker are held in memory.
+func AnalyzeImage(ctx context.Context, sess *session.Session, img *image.Image) {
+	log := log.Log
+	tid := ctx.Value(TID)
+	cfg := sess.Config.Global
+	repo := coreapi.Repository{Name: img.Name}
+
+	analyzeImageConfig(ctx, sess, repo, imageEnvPath, img.Config.Env)
+	// every line of the history is a layer, build commands spanning several lines are joined
+	var history []string
+	for _, h := range img.Config.History {
+		history = append(history, strings.ReplaceAll(h, "\n", " "))
+	}
+	analyzeImageConfig(ctx, sess, repo, imageHistoryPath, history)
+
+	threads := cfg.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	var wg sync.WaitGroup
+	files := make(chan imageFile, threads)
+	for i := 0; i < threads; i++ {
+		ctxworker := context.WithValue(ctx, TID, i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range files {
+				analyzeFile(ctxworker, sess, f.src, f.mf, f.mf.Path)
+			}
+		}()
+	}
+
+	err := img.Walk(func(f image.File) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mf := matchfile.New(f.Path)
+		limit := cfg.MaxFileSize * 1024 * 1024
+		if isArchive(ctx, cfg, mf) {
+			limit = cfg.ArchiveMaxSize * 1024 * 1024
+		}
+		if f.Size > limit {
+			log.Debug("[THREAD #%d][%s] %s in layer %s is too large, ignoring", tid, img.Name, f.Path, f.Layer)
+			sess.State.Stats.IncrementFilesTotal()
+			sess.State.Stats.IncrementIgnoredFiles()
+			return nil
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			// the rest of the layer can't be read either, which is reported when the walk gets to it
+			log.Debug("[THREAD #%d][%s] %s in layer %s can't be read, %s", tid, img.Name, f.Path, f.Layer, err.Error())
+			return nil
+		}
+		mf.Content = data
+
+		src := source{repo: repo, layer: f.Layer}
+		if f.Removed {
+			src.action = actionRemoved
+		}
+		select {
+		case files <- imageFile{mf: mf, src: src}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, func(digest string, err error) {
+		log.Warn("Layer %s of %s is skipped, %s", digest, img.Name, err.Error())
+	})
+	close(files)
+	wg.Wait()
+	if err != nil {
+		log.Error("Scanning %s stopped, %s", img.Name, err.Error())
+	}
+}
+
+// analyzeImageConfig will scan the lines of the config as a file
+func analyzeImageConfig(ctx context.Context, sess *session.Session, repo coreapi.Repository, path string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	mf := matchfile.New(path)
+	mf.Content = []byte(strings.Join(lines, "\n") + "\n")
+	analyzeFile(ctx, sess, source{repo: repo}, mf, path)
+}
@@ -0,0 +1,480 @@
+// Package image reads container images saved with docker save, or stored as OCI image layouts, so that
+// the files of their layers and the config they run with can be scanned.
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Whiteout files mark the files of lower layers that a layer removes
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq" // removes all the files of lower layers in its directory
+)
+
+// ErrUnsupported is returned for layers whose compression can't be read, e.g. zstd
+var ErrUnsupported = errors.New("unsupported layer compression")
+
+// Image is a container image
+type Image struct {
+	Name   string // the tags of the image, or the digest of its manifest if it has none
+	Config Config
+	layers []layer
+	blobs  store
+}
+
+// Config holds the parts of the image config that secrets are passed in at build time
+type Config struct {
+	Env     []string // KEY=value
+	History []string // the commands that built every layer, including the ones that didn't change files
+}
+
+// File is a regular file of a layer of the image
+type File struct {
+	io.Reader
+	Path  string // absolute path within the filesystem of the image
+	Layer string // digest of the layer
+	Size  int64
+	// Removed is set for files that a later layer deleted or replaced, they're still in the image though
+	Removed bool
+}
+
+type layer struct {
+	digest string
+	blob   string // name of the blob within the store
+}
+
+// Reader holds the images read from a file or directory
+type Reader struct {
+	Images []*Image
+	blobs  store
+}
+
+// Close will release the file the images are read from
+func (r *Reader) Close() error {
+	return r.blobs.Close()
+}
+
+// Open will read the images of a docker save tarball, an OCI image layout directory, or a tarball of an
+// OCI image layout
+func Open(name string) (*Reader, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	var blobs store
+	if fi.IsDir() {
+		blobs = dirStore(name)
+	} else {
+		blobs, err = openTarStore(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var images []*Image
+	switch {
+	case blobs.Has("manifest.json"):
+		images, err = readDockerManifest(blobs)
+	case blobs.Has("index.json"):
+		images, err = readOCIIndex(blobs, "index.json", "")
+	default:
+		err = errors.New("neither a docker save tarball nor an OCI image layout")
+	}
+	if err != nil {
+		blobs.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &Reader{Images: images, blobs: blobs}, nil
+}
+
+// imageConfig is the part of the image config that is read
+type imageConfig struct {
+	Config struct {
+		Env []string `json:"Env"`
+	} `json:"config"`
+	History []struct {
+		CreatedBy string `json:"created_by"`
+	} `json:"history"`
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+func readConfig(blobs store, name string) (imageConfig, Config, error) {
+	var ic imageConfig
+	if err := readJSON(blobs, name, &ic); err != nil {
+		return ic, Config{}, fmt.Errorf("config: %w", err)
+	}
+	cfg := Config{Env: ic.Config.Env}
+	for _, h := range ic.History {
+		cfg.History = append(cfg.History, h.CreatedBy)
+	}
+	return ic, cfg, nil
+}
+
+// readDockerManifest reads the images of a docker save tarball
+func readDockerManifest(blobs store) ([]*Image, error) {
+	var manifest []struct {
+		Config   string   `json:"Config"`
+		RepoTags []string `json:"RepoTags"`
+		Layers   []string `json:"Layers"`
+	}
+	if err := readJSON(blobs, "manifest.json", &manifest); err != nil {
+		return nil, err
+	}
+	var images []*Image
+	for _, m := range manifest {
+		ic, cfg, err := readConfig(blobs, m.Config)
+		if err != nil {
+			return nil, err
+		}
+		img := &Image{Name: strings.Join(m.RepoTags, ", "), Config: cfg, blobs: blobs}
+		if img.Name == "" {
+			img.Name = strings.TrimSuffix(blobDigest(m.Config), ".json")
+		}
+		for i, l := range m.Layers {
+			// older versions of docker name layers by id rather than digest, their diff ids are digests
+			digest := blobDigest(l)
+			if digest == l && i < len(ic.RootFS.DiffIDs) {
+				digest = ic.RootFS.DiffIDs[i]
+			}
+			img.layers = append(img.layers, layer{digest: digest, blob: l})
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+	Platform    *struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+		Variant      string `json:"variant"`
+	} `json:"platform"`
+}
+
+// readOCIIndex reads the images of an OCI image index, following the indexes nested in it, e.g. the ones
+// of multi-platform images
+func readOCIIndex(blobs store, file, ref string) ([]*Image, error) {
+	var index struct {
+		Manifests []descriptor `json:"manifests"`
+	}
+	if err := readJSON(blobs, file, &index); err != nil {
+		return nil, err
+	}
+	var images []*Image
+	for _, d := range index.Manifests {
+		name := ref
+		if v := d.Annotations["io.containerd.image.name"]; v != "" {
+			name = v
+		} else if v := d.Annotations["org.opencontainers.image.ref.name"]; v != "" {
+			name = v
+		}
+		var platform string
+		if d.Platform != nil {
+			platform = " (" + path.Join(d.Platform.OS, d.Platform.Architecture, d.Platform.Variant) + ")"
+		}
+		blob, err := blobPath(d.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		switch d.MediaType {
+		case "application/vnd.oci.image.index.v1+json", "application/vnd.docker.distribution.manifest.list.v2+json":
+			nested, err := readOCIIndex(blobs, blob, strings.TrimSpace(name+platform))
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, nested...)
+			continue
+		}
+
+		var manifest struct {
+			Config descriptor   `json:"config"`
+			Layers []descriptor `json:"layers"`
+		}
+		if err := readJSON(blobs, blob, &manifest); err != nil {
+			return nil, err
+		}
+		configBlob, err := blobPath(manifest.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		_, cfg, err := readConfig(blobs, configBlob)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			name = d.Digest
+		}
+		img := &Image{Name: name + platform, Config: cfg, blobs: blobs}
+		for _, l := range manifest.Layers {
+			layerBlob, err := blobPath(l.Digest)
+			if err != nil {
+				return nil, err
+			}
+			img.layers = append(img.layers, layer{digest: l.Digest, blob: layerBlob})
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// blobPath returns the path of a blob within an OCI image layout
+func blobPath(digest string) (string, error) {
+	alg, hex, ok := strings.Cut(digest, ":")
+	if !ok || alg == "" || hex == "" || strings.ContainsAny(digest, `/\`) {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return path.Join("blobs", alg, hex), nil
+}
+
+// blobDigest returns the digest of a blob from its path, if it's stored by digest
+func blobDigest(name string) string {
+	dir, hex := path.Split(name)
+	if alg := path.Base(dir); path.Dir(strings.TrimSuffix(dir, "/")) == "blobs" {
+		return alg + ":" + hex
+	}
+	return name
+}
+
+func readJSON(blobs store, name string, v interface{}) error {
+	rc, err := blobs.Open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Walk will call fn for every regular file of every layer, from the top layer down, so that the files
+// that later layers remove are known. Whiteout files aren't reported. Layers that can't be read are
+// reported to skip and the walk continues, an error returned by fn stops it.
+func (img *Image) Walk(fn func(File) error, skip func(digest string, err error)) error {
+	upper := make(whiteouts)
+	for i := len(img.layers) - 1; i >= 0; i-- {
+		l := img.layers[i]
+		current := make(whiteouts)
+		err := img.walkLayer(l, func(hdr *tar.Header, r io.Reader) error {
+			name := cleanPath(hdr.Name)
+			if name == "" {
+				return nil
+			}
+			dir, base := path.Split(name)
+			dir = strings.TrimSuffix(dir, "/")
+			switch {
+			case base == whiteoutOpaque:
+				current.add(dir, true)
+				return nil
+			case strings.HasPrefix(base, whiteoutPrefix):
+				current.add(path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), true)
+				return nil
+			case hdr.Typeflag == tar.TypeDir:
+				current.add(name, false)
+				return nil
+			}
+			// other files, e.g. symlinks, replace the directories of lower layers
+			current.add(name, true)
+			if hdr.Typeflag != tar.TypeReg {
+				return nil
+			}
+			return fn(File{Reader: r, Path: "/" + name, Layer: l.digest, Size: hdr.Size, Removed: upper.hides(name)})
+		})
+		if err != nil {
+			var fnErr callbackError
+			if errors.As(err, &fnErr) {
+				return fnErr.err
+			}
+			skip(l.digest, err)
+		}
+		upper.merge(current)
+	}
+	return nil
+}
+
+// callbackError is an error returned by the callback of Walk, rather than by reading a layer
+type callbackError struct {
+	err error
+}
+
+func (e callbackError) Error() string {
+	return e.err.Error()
+}
+
+func (img *Image) walkLayer(l layer, fn func(*tar.Header, io.Reader) error) error {
+	rc, err := img.blobs.Open(l.blob)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	br := bufio.NewReader(rc)
+	magic, _ := br.Peek(4)
+	var r io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return fmt.Errorf("%w, zstd", ErrUnsupported)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(hdr, tr); err != nil {
+			return callbackError{err}
+		}
+	}
+}
+
+// whiteouts holds the paths that layers put over the files of lower layers. A path that is set to true
+// hides everything under it, e.g. a whiteout, an opaque directory or a file replacing a directory.
+type whiteouts map[string]bool
+
+func (w whiteouts) add(name string, hides bool) {
+	w[name] = w[name] || hides
+}
+
+func (w whiteouts) merge(other whiteouts) {
+	for name, hides := range other {
+		w.add(name, hides)
+	}
+}
+
+// hides returns true if a file at the path is replaced or removed
+func (w whiteouts) hides(name string) bool {
+	if _, ok := w[name]; ok {
+		return true
+	}
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if w[dir] {
+			return true
+		}
+	}
+	return w[""]
+}
+
+// cleanPath returns the path of a tar entry relative to the root, without ./ or a trailing slash
+func cleanPath(name string) string {
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
+
+// store holds the files of an image, by their path within a docker save tarball or an OCI image layout
+type store interface {
+	Has(name string) bool
+	Open(name string) (io.ReadCloser, error)
+	Close() error
+}
+
+type dirStore string
+
+func (d dirStore) Has(name string) bool {
+	_, err := os.Stat(filepath.Join(string(d), filepath.FromSlash(name)))
+	return err == nil
+}
+
+func (d dirStore) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(string(d), filepath.FromSlash(cleanPath(name))))
+}
+
+func (d dirStore) Close() error {
+	return nil
+}
+
+// tarStore reads the files of a tarball in place, by their offset within it
+type tarStore struct {
+	f       *os.File
+	entries map[string]tarEntry
+}
+
+type tarEntry struct {
+	offset, size int64
+	link         string // the target of symlinks, e.g. the layers of docker save pointing to their blobs
+}
+
+func openTarStore(name string) (*tarStore, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &tarStore{f: f, entries: make(map[string]tarEntry)}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return s, nil
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		name := cleanPath(hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			// the tar reader reads the headers only, so the file is at the data of the entry
+			offset, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			s.entries[name] = tarEntry{offset: offset, size: hdr.Size}
+		case tar.TypeSymlink:
+			s.entries[name] = tarEntry{link: cleanPath(path.Join(path.Dir(name), hdr.Linkname))}
+		}
+	}
+}
+
+func (s *tarStore) entry(name string) (tarEntry, bool) {
+	e, ok := s.entries[cleanPath(name)]
+	for i := 0; ok && e.link != "" && i < 8; i++ {
+		e, ok = s.entries[e.link]
+	}
+	return e, ok && e.link == ""
+}
+
+func (s *tarStore) Has(name string) bool {
+	_, ok := s.entry(name)
+	return ok
+}
+
+func (s *tarStore) Open(name string) (io.ReadCloser, error) {
+	e, ok := s.entry(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return io.NopCloser(io.NewSectionReader(s.f, e.offset, e.size)), nil
+}
+
+func (s *tarStore) Close() error {
+	return s.f.Close()
+}
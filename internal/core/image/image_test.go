@@ -0,0 +1,268 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entry is a file of a layer, directories end with a slash and symlinks have a target
+type entry struct {
+	name, content, link string
+}
+
+var testLayers = [][]entry{
+	{{name: "app/"}, {name: "app/.npmrc", content: "//registry.npmjs.org/:_authToken=npm_token"}, {name: "app/config/"}, {name: "app/config/db.yaml", content: "password: one"}, {name: "etc/motd", content: "hi"}},
+	{{name: "app/.wh..npmrc"}, {name: "app/config/.wh..wh..opq"}, {name: "app/config/app.yaml", content: "password: two"}, {name: "etc/motd", content: "hello"}},
+	{{name: "app/run.sh", content: "exec app"}, {name: "app/bin", link: "/usr/bin"}},
+}
+
+var testConfig = map[string]interface{}{
+	"config": map[string]interface{}{"Env": []string{"PATH=/usr/bin", "NPM_TOKEN=npm_token"}},
+	"history": []map[string]interface{}{
+		{"created_by": "/bin/sh -c #(nop) ENV NPM_TOKEN=npm_token", "empty_layer": true},
+		{"created_by": "COPY . /app"},
+		{"created_by": "RUN rm /app/.npmrc"},
+		{"created_by": "COPY run.sh /app"},
+	},
+}
+
+// walked is a file reported by Walk
+type walked struct {
+	Path, Layer, Content string
+	Removed              bool
+}
+
+func TestOpen_docker(t *testing.T) {
+	var layers [][]byte
+	var diffIDs []string
+	for _, l := range testLayers {
+		data := makeLayer(t, l, false)
+		layers = append(layers, data)
+		diffIDs = append(diffIDs, digest(data))
+	}
+	cfg := map[string]interface{}{"config": testConfig["config"], "history": testConfig["history"]}
+	cfg["rootfs"] = map[string]interface{}{"type": "layers", "diff_ids": diffIDs}
+	config := mustJSON(t, cfg)
+
+	// layers named by id, as written by older versions of docker
+	files := map[string][]byte{"abc123.json": config}
+	var names []string
+	for i, l := range layers {
+		name := string(rune('a'+i)) + "/layer.tar"
+		files[name] = l
+		names = append(names, name)
+	}
+	files["manifest.json"] = mustJSON(t, []map[string]interface{}{{"Config": "abc123.json", "RepoTags": []string{"app:1.0", "app:latest"}, "Layers": names}})
+
+	file := filepath.Join(t.TempDir(), "app.tar")
+	require.NoError(t, os.WriteFile(file, makeTar(t, files, nil), 0600))
+	r, err := Open(file)
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Len(t, r.Images, 1)
+	img := r.Images[0]
+	assert.Equal(t, "app:1.0, app:latest", img.Name)
+	assert.Equal(t, Config{
+		Env:     []string{"PATH=/usr/bin", "NPM_TOKEN=npm_token"},
+		History: []string{"/bin/sh -c #(nop) ENV NPM_TOKEN=npm_token", "COPY . /app", "RUN rm /app/.npmrc", "COPY run.sh /app"},
+	}, img.Config)
+	assert.Equal(t, wantWalked(diffIDs), walk(t, img))
+}
+
+func TestOpen_dockerBlobs(t *testing.T) {
+	// newer versions of docker store blobs by digest, with the layers linking to them
+	files := make(map[string][]byte)
+	links := make(map[string]string)
+	var names, digests []string
+	for i, l := range testLayers {
+		data := makeLayer(t, l, false)
+		d := digest(data)
+		files["blobs/sha256/"+d[7:]] = data
+		name := string(rune('a'+i)) + "/layer.tar"
+		links[name] = "../blobs/sha256/" + d[7:]
+		names = append(names, name)
+		digests = append(digests, d)
+	}
+	config := mustJSON(t, map[string]interface{}{"config": testConfig["config"], "rootfs": map[string]interface{}{"type": "layers", "diff_ids": digests}})
+	files["blobs/sha256/"+digest(config)[7:]] = config
+	files["manifest.json"] = mustJSON(t, []map[string]interface{}{{"Config": "blobs/sha256/" + digest(config)[7:], "Layers": names}})
+
+	file := filepath.Join(t.TempDir(), "app.tar")
+	require.NoError(t, os.WriteFile(file, makeTar(t, files, links), 0600))
+	r, err := Open(file)
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Len(t, r.Images, 1)
+	assert.Equal(t, digest(config), r.Images[0].Name)
+	assert.Equal(t, wantWalked(digests), walk(t, r.Images[0]))
+}
+
+func TestOpen_oci(t *testing.T) {
+	dir := t.TempDir()
+	write := func(data []byte) string {
+		d := digest(data)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", d[7:]), data, 0600))
+		return d
+	}
+
+	var layers []map[string]interface{}
+	var digests []string
+	for _, l := range testLayers {
+		d := write(makeLayer(t, l, true))
+		layers = append(layers, map[string]interface{}{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": d})
+		digests = append(digests, d)
+	}
+	config := write(mustJSON(t, testConfig))
+	manifest := write(mustJSON(t, map[string]interface{}{
+		"schemaVersion": 2,
+		"config":        map[string]interface{}{"mediaType": "application/vnd.oci.image.config.v1+json", "digest": config},
+		"layers":        layers,
+	}))
+	index := write(mustJSON(t, map[string]interface{}{"manifests": []map[string]interface{}{
+		{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": manifest, "platform": map[string]string{"os": "linux", "architecture": "arm64", "variant": "v8"}},
+	}}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion": "1.0.0"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), mustJSON(t, map[string]interface{}{"manifests": []map[string]interface{}{
+		{"mediaType": "application/vnd.oci.image.index.v1+json", "digest": index, "annotations": map[string]string{"org.opencontainers.image.ref.name": "app:1.0"}},
+	}}), 0600))
+
+	r, err := Open(dir)
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Len(t, r.Images, 1)
+	assert.Equal(t, "app:1.0 (linux/arm64/v8)", r.Images[0].Name)
+	assert.Equal(t, wantWalked(digests), walk(t, r.Images[0]))
+}
+
+func TestOpen_invalid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Open(dir)
+	assert.Error(t, err)
+
+	file := filepath.Join(dir, "app.tar")
+	require.NoError(t, os.WriteFile(file, makeTar(t, map[string][]byte{"manifest.json": []byte("[{")}, nil), 0600))
+	_, err = Open(file)
+	assert.Error(t, err)
+}
+
+func TestWalk_unsupported(t *testing.T) {
+	zstd := []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0, 0, 0}
+	file := filepath.Join(t.TempDir(), "app.tar")
+	require.NoError(t, os.WriteFile(file, makeTar(t, map[string][]byte{
+		"manifest.json": mustJSON(t, []map[string]interface{}{{"Config": "config.json", "Layers": []string{"a/layer.tar", "b/layer.tar"}}}),
+		"config.json":   mustJSON(t, testConfig),
+		"a/layer.tar":   zstd,
+		"b/layer.tar":   makeLayer(t, testLayers[2], false),
+	}, nil), 0600))
+	r, err := Open(file)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var skipped []string
+	var files []string
+	err = r.Images[0].Walk(func(f File) error {
+		files = append(files, f.Path)
+		return nil
+	}, func(digest string, err error) {
+		assert.ErrorIs(t, err, ErrUnsupported)
+		skipped = append(skipped, digest)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/app/run.sh"}, files)
+	assert.Equal(t, []string{"a/layer.tar"}, skipped)
+}
+
+func wantWalked(digests []string) []walked {
+	return []walked{
+		{Path: "/app/run.sh", Layer: digests[2], Content: "exec app"},
+		{Path: "/app/config/app.yaml", Layer: digests[1], Content: "password: two"},
+		{Path: "/etc/motd", Layer: digests[1], Content: "hello"},
+		{Path: "/app/.npmrc", Layer: digests[0], Content: "//registry.npmjs.org/:_authToken=npm_token", Removed: true},
+		{Path: "/app/config/db.yaml", Layer: digests[0], Content: "password: one", Removed: true},
+		{Path: "/etc/motd", Layer: digests[0], Content: "hi", Removed: true},
+	}
+}
+
+func walk(t *testing.T, img *Image) []walked {
+	var got []walked
+	err := img.Walk(func(f File) error {
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), f.Size)
+		got = append(got, walked{Path: f.Path, Layer: f.Layer, Content: string(data), Removed: f.Removed})
+		return nil
+	}, func(digest string, err error) {
+		t.Errorf("layer %s skipped: %s", digest, err)
+	})
+	require.NoError(t, err)
+	return got
+}
+
+func makeLayer(t *testing.T, entries []entry, compress bool) []byte {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(e.content))}
+		switch {
+		case e.link != "":
+			hdr = &tar.Header{Name: e.name, Typeflag: tar.TypeSymlink, Linkname: e.link, Mode: 0777}
+		case e.name[len(e.name)-1] == '/':
+			hdr = &tar.Header{Name: e.name, Typeflag: tar.TypeDir, Mode: 0755}
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+	return buf.Bytes()
+}
+
+func makeTar(t *testing.T, files map[string][]byte, links map[string]string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	for name, target := range links {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeSymlink, Linkname: target, Mode: 0777}))
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,114 @@
+package keyvalue
+
+import (
+	"strings"
+)
+
+// parseINI will read the key = value or key: value lines of INI and properties files. Keys are prefixed
+// with their [section] and lines ending with a backslash continue on the next line.
+func parseINI(data []byte) []Pair {
+	var res []Pair
+	var section string
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.ContainsAny(trimmed[:1], "#;!") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:sep])
+		value, col := trimValue(line, sep+1)
+		lineNum := i + 1
+		for strings.HasSuffix(value, `\`) && i+1 < len(lines) {
+			i++
+			value = strings.TrimSuffix(value, `\`) + strings.TrimSpace(strings.TrimRight(lines[i], "\r"))
+		}
+		if key == "" {
+			continue
+		}
+		res = append(res, Pair{Key: joinKey(section, key), Value: unquote(value), Line: lineNum, Column: col})
+	}
+	return res
+}
+
+// parseDotenv will read the KEY=value lines of dotenv files. Double quoted values may span several lines,
+// e.g. private keys, and unquoted values end at an inline comment.
+func parseDotenv(data []byte) []Pair {
+	var res []Pair
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		sep := strings.Index(line, "=")
+		if sep < 0 {
+			continue
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[:sep]), "export "))
+		value, col := trimValue(line, sep+1)
+		lineNum := i + 1
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			// read until the closing quote
+			for !closedQuote(value) && i+1 < len(lines) {
+				i++
+				value += "\n" + strings.TrimRight(lines[i], "\r")
+			}
+			if end := strings.LastIndex(value, `"`); end > 0 {
+				value = value[:end+1]
+			}
+			value = strings.ReplaceAll(unquote(value), `\n`, "\n")
+		case strings.HasPrefix(value, `'`):
+			value = unquote(value)
+		default:
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+		if key == "" {
+			continue
+		}
+		res = append(res, Pair{Key: key, Value: value, Line: lineNum, Column: col})
+	}
+	return res
+}
+
+// trimValue returns the value starting at the offset without surrounding whitespace, and its 1-based column
+func trimValue(line string, offset int) (string, int) {
+	value := strings.TrimLeft(line[offset:], " \t")
+	col := len(line) - len(value) + 1
+	return strings.TrimSpace(value), col
+}
+
+// closedQuote returns true if the double quoted value has its closing quote
+func closedQuote(value string) bool {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return true
+		}
+	}
+	return false
+}
+
+// unquote will remove the quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,82 @@
+package keyvalue
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// parseJSON will walk the tokens of the document, as the decoder doesn't keep the position of values
+func parseJSON(data []byte) ([]Pair, error) {
+	p := jsonParser{dec: json.NewDecoder(bytes.NewReader(data)), data: data, lines: newLineIndex(data)}
+	p.dec.UseNumber()
+	err := p.walk("")
+	return p.res, err
+}
+
+type jsonParser struct {
+	dec   *json.Decoder
+	data  []byte
+	lines lineIndex
+	res   []Pair
+}
+
+func (p *jsonParser) walk(path string) error {
+	start := p.valueStart(int(p.dec.InputOffset()))
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	var value string
+	switch v := tok.(type) {
+	case json.Delim:
+		return p.walkContainer(path, v)
+	case string:
+		value = v
+	case json.Number:
+		value = v.String()
+	case bool:
+		value = strconv.FormatBool(v)
+	default: // null
+		return nil
+	}
+	if path != "" {
+		line, col := p.lines.position(start)
+		p.res = append(p.res, Pair{Key: path, Value: value, Line: line, Column: col})
+	}
+	return nil
+}
+
+func (p *jsonParser) walkContainer(path string, delim json.Delim) error {
+	for i := 0; p.dec.More(); i++ {
+		key := fmt.Sprintf("%s[%d]", path, i)
+		if delim == '{' {
+			tok, err := p.dec.Token()
+			if err != nil {
+				return err
+			}
+			key = joinKey(path, fmt.Sprint(tok))
+		}
+		if err := p.walk(key); err != nil {
+			return err
+		}
+	}
+	// the closing delimiter
+	_, err := p.dec.Token()
+	return err
+}
+
+// valueStart returns the offset of the next token, skipping the separators the decoder hasn't consumed yet
+func (p *jsonParser) valueStart(offset int) int {
+	for offset < len(p.data) {
+		switch p.data[offset] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
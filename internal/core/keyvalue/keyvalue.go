@@ -0,0 +1,105 @@
+// Package keyvalue extracts the key/value pairs of structured configuration files, so that signatures
+// can match on the names of the keys and the shape of the values separately
+package keyvalue
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatYAML       = "yaml"
+	FormatJSON       = "json"
+	FormatTOML       = "toml"
+	FormatINI        = "ini"
+	FormatProperties = "properties"
+	FormatDotenv     = "dotenv"
+	FormatXML        = "xml"
+)
+
+// Pair is a scalar value of a structured file. Nested keys are joined with dots and array elements
+// are indexed, e.g. spring.datasource.password or servers[0].token.
+type Pair struct {
+	Key    string
+	Value  string
+	Line   int // 1-based position of the value, as reported by the parser
+	Column int
+}
+
+// Format returns the format of the file based on its name, or an empty string if it isn't supported
+func Format(filename string) string {
+	base := strings.ToLower(filepath.Base(filename))
+	if base == ".env" || strings.HasPrefix(base, ".env.") {
+		return FormatDotenv
+	}
+	switch filepath.Ext(base) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	case ".ini", ".cfg":
+		return FormatINI
+	case ".properties":
+		return FormatProperties
+	case ".env":
+		return FormatDotenv
+	case ".xml", ".config":
+		return FormatXML
+	}
+	return ""
+}
+
+// Parse will extract the key/value pairs of the file. Returns no pairs for unsupported formats.
+func Parse(filename string, data []byte) ([]Pair, error) {
+	return ParseFormat(Format(filename), data)
+}
+
+// ParseFormat will extract the key/value pairs of data in the given format
+func ParseFormat(format string, data []byte) ([]Pair, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(data)
+	case FormatJSON:
+		return parseJSON(data)
+	case FormatTOML:
+		return parseTOML(data)
+	case FormatINI, FormatProperties:
+		return parseINI(data), nil
+	case FormatDotenv:
+		return parseDotenv(data), nil
+	case FormatXML:
+		return parseXML(data)
+	}
+	return nil, nil
+}
+
+// joinKey appends the key to the path of its parent
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lineIndex converts byte offsets into line and column numbers, for parsers reporting offsets only
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	idx := lineIndex{0}
+	for i, c := range data {
+		if c == '\n' {
+			idx = append(idx, i+1)
+		}
+	}
+	return idx
+}
+
+// position returns the 1-based line and column of the offset
+func (idx lineIndex) position(offset int) (int, int) {
+	line := sort.Search(len(idx), func(i int) bool { return idx[i] > offset }) - 1
+	return line + 1, offset - idx[line] + 1
+}
@@ -0,0 +1,144 @@
+package keyvalue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"deploy/secret.yaml":              FormatYAML,
+		"values.YML":                      FormatYAML,
+		"package.json":                    FormatJSON,
+		"Cargo.toml":                      FormatTOML,
+		"setup.cfg":                       FormatINI,
+		"src/main/application.properties": FormatProperties,
+		".env":                            FormatDotenv,
+		".env.production":                 FormatDotenv,
+		"prod.env":                        FormatDotenv,
+		"web.config":                      FormatXML,
+		"pom.xml":                         FormatXML,
+		"main.go":                         "",
+		"environment":                     "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, Format(name), name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want []Pair
+	}{
+		{"yaml", "config.yaml", `
+spring:
+  datasource:
+    password: s3cr3t
+servers:
+  - token: "abc"
+empty:
+---
+data:
+  key: |
+    line1
+    line2
+`, []Pair{
+			{"spring.datasource.password", "s3cr3t", 4, 15},
+			{"servers[0].token", "abc", 6, 12},
+			{"data.key", "line1\nline2\n", 10, 8},
+		}},
+		{"json", "config.json", `{
+  "db": {"password": "s3cr3t", "port": 5432},
+  "keys": ["a", true, null]
+}`, []Pair{
+			{"db.password", "s3cr3t", 2, 22},
+			{"db.port", "5432", 2, 40},
+			{"keys[0]", "a", 3, 12},
+			{"keys[1]", "true", 3, 17},
+		}},
+		{"toml", "config.toml", `
+title = "app"
+[database]
+password = 's3cr3t'
+ports = [8000, 8001]
+[[servers]]
+token = { value = "abc" }
+[[servers]]
+token = { value = "def" }
+`, []Pair{
+			{"title", "app", 2, 9},
+			{"database.password", "s3cr3t", 4, 12},
+			{"database.ports[0]", "8000", 5, 10},
+			{"database.ports[1]", "8001", 5, 16},
+			{"servers[0].token.value", "abc", 7, 19},
+			{"servers[1].token.value", "def", 9, 19},
+		}},
+		{"ini", "app.ini", `
+; comment
+global = 1
+[database]
+password = "s3cr3t"
+`, []Pair{
+			{"global", "1", 3, 10},
+			{"database.password", "s3cr3t", 5, 12},
+		}},
+		{"properties", "application.properties", `
+# comment
+spring.datasource.password: s3cr3t
+long.value = first,\
+    second
+`, []Pair{
+			{"spring.datasource.password", "s3cr3t", 3, 29},
+			{"long.value", "first,second", 4, 14},
+		}},
+		{"dotenv", ".env", `
+export API_TOKEN=abc123 # inline comment
+DB_PASSWORD='s3cr3t'
+PRIVATE_KEY="-----BEGIN KEY-----
+MIIEvQIBADANBg
+-----END KEY-----"
+ESCAPED="a\nb"
+`, []Pair{
+			{"API_TOKEN", "abc123", 2, 18},
+			{"DB_PASSWORD", "s3cr3t", 3, 13},
+			{"PRIVATE_KEY", "-----BEGIN KEY-----\nMIIEvQIBADANBg\n-----END KEY-----", 4, 13},
+			{"ESCAPED", "a\nb", 7, 9},
+		}},
+		{"xml", "web.config", `<?xml version="1.0"?>
+<configuration>
+  <appSettings>
+    <add key="ApiKey" value="abc123" />
+  </appSettings>
+  <connectionStrings>
+    <add name="Default" connectionString="Server=db;Password=s3cr3t" />
+  </connectionStrings>
+  <password>s3cr3t</password>
+</configuration>`, []Pair{
+			{"configuration.appSettings.add@key", "ApiKey", 4, 15},
+			{"configuration.appSettings.ApiKey", "abc123", 4, 30},
+			{"configuration.connectionStrings.add@name", "Default", 7, 16},
+			{"configuration.connectionStrings.Default@connectionString", "Server=db;Password=s3cr3t", 7, 43},
+			{"configuration.password", "s3cr3t", 9, 13},
+		}},
+		{"unsupported", "main.go", `password := "s3cr3t"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.file, []byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for file, data := range map[string]string{"a.yaml": "{ [ invalid", "a.json": "{ [ invalid", "a.toml": "{ [ invalid", "a.xml": "<a><b"} {
+		_, err := Parse(file, []byte(data))
+		assert.Error(t, err, file)
+	}
+}
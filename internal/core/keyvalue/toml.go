@@ -0,0 +1,66 @@
+package keyvalue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// parseTOML will walk the expressions of the document with the low-level parser, which keeps the position
+// of every value
+func parseTOML(data []byte) ([]Pair, error) {
+	var res []Pair
+	var table string
+	arrays := make(map[string]int) // number of elements of every array table seen so far
+
+	p := unstable.Parser{}
+	p.Reset(data)
+	for p.NextExpression() {
+		e := p.Expression()
+		switch e.Kind {
+		case unstable.Table:
+			table = tomlKey(e.Key())
+		case unstable.ArrayTable:
+			name := tomlKey(e.Key())
+			table = fmt.Sprintf("%s[%d]", name, arrays[name])
+			arrays[name]++
+		case unstable.KeyValue:
+			res = walkTOML(res, &p, joinKey(table, tomlKey(e.Key())), e.Value())
+		}
+	}
+	return res, p.Error()
+}
+
+func walkTOML(res []Pair, p *unstable.Parser, path string, n *unstable.Node) []Pair {
+	switch n.Kind {
+	case unstable.InlineTable:
+		it := n.Children()
+		for it.Next() {
+			kv := it.Node()
+			res = walkTOML(res, p, joinKey(path, tomlKey(kv.Key())), kv.Value())
+		}
+	case unstable.Array:
+		it := n.Children()
+		for i := 0; it.Next(); i++ {
+			res = walkTOML(res, p, fmt.Sprintf("%s[%d]", path, i), it.Node())
+		}
+	default:
+		// strings are unescaped into a copy, the raw range points to the input
+		r := n.Raw
+		if r.Length == 0 {
+			r = p.Range(n.Data)
+		}
+		pos := p.Shape(r).Start
+		res = append(res, Pair{Key: path, Value: string(n.Data), Line: pos.Line, Column: pos.Column})
+	}
+	return res
+}
+
+func tomlKey(it unstable.Iterator) string {
+	var parts []string
+	for it.Next() {
+		parts = append(parts, string(it.Node().Data))
+	}
+	return strings.Join(parts, ".")
+}
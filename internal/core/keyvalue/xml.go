@@ -0,0 +1,78 @@
+package keyvalue
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// parseXML will report the text of every element and the value of every attribute, keyed by the path of
+// the element, e.g. configuration.database.password or configuration.database@password. The key/value
+// elements of .NET configuration files, e.g. <add key="ApiKey" value="..." />, are keyed by the path of
+// their parent and the value of their key or name attribute, e.g. configuration.appSettings.ApiKey.
+func parseXML(data []byte) ([]Pair, error) {
+	var res []Pair
+	var stack []string
+	lines := newLineIndex(data)
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return res, err
+		}
+		raw := data[start:dec.InputOffset()]
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			path := strings.Join(stack, ".")
+			var name string
+			for _, a := range t.Attr {
+				if a.Name.Local == "key" || a.Name.Local == "name" {
+					name = a.Value
+				}
+			}
+			for _, a := range t.Attr {
+				line, col := lines.position(start + attrOffset(raw, a.Name.Local))
+				key := path + "@" + a.Name.Local
+				if name != "" && a.Name.Local != "key" && a.Name.Local != "name" {
+					key = joinKey(strings.Join(stack[:len(stack)-1], "."), name)
+					if a.Name.Local != "value" {
+						key += "@" + a.Name.Local
+					}
+				}
+				res = append(res, Pair{Key: key, Value: a.Value, Line: line, Column: col})
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" || len(stack) == 0 {
+				continue
+			}
+			offset := start + bytes.IndexFunc(raw, func(r rune) bool { return !strings.ContainsRune(" \t\r\n", r) })
+			line, col := lines.position(offset)
+			res = append(res, Pair{Key: strings.Join(stack, "."), Value: text, Line: line, Column: col})
+		}
+	}
+}
+
+// attrOffset returns the offset of the value of the attribute within the raw element
+func attrOffset(raw []byte, name string) int {
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*=\s*["']`)
+	if loc := re.FindIndex(raw); loc != nil {
+		return loc[1]
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package keyvalue
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML will walk every document of the stream. Aliases are skipped, their values are reported
+// where they're defined.
+func parseYAML(data []byte) ([]Pair, error) {
+	var res []Pair
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return res, err
+		}
+		res = walkYAML(res, "", &doc)
+	}
+}
+
+func walkYAML(res []Pair, path string, n *yaml.Node) []Pair {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			res = walkYAML(res, path, c)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			res = walkYAML(res, joinKey(path, n.Content[i].Value), n.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			res = walkYAML(res, fmt.Sprintf("%s[%d]", path, i), c)
+		}
+	case yaml.ScalarNode:
+		if path != "" && n.Tag != "!!null" {
+			res = append(res, Pair{Key: path, Value: n.Value, Line: n.Line, Column: n.Column})
+		}
+	}
+	return res
+}
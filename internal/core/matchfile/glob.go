@@ -0,0 +1,90 @@
+package matchfile
+
+import (
+	"path"
+	"strings"
+)
+
+// Glob reports whether the slash separated name matches the pattern, which has the syntax of path.Match
+// extended with ** for any number of directories, e.g. **/*.yaml, and {a,b} for alternatives, e.g.
+// config/*.{yaml,yml}. The pattern matches the whole name.
+func Glob(pattern, name string) bool {
+	names := strings.Split(name, "/")
+	for _, p := range expandBraces(pattern) {
+		if globSegments(strings.Split(p, "/"), names) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGlob will return path.ErrBadPattern if the pattern is malformed
+func ValidateGlob(pattern string) error {
+	if strings.Count(pattern, "{") != strings.Count(pattern, "}") {
+		return path.ErrBadPattern
+	}
+	for _, p := range expandBraces(pattern) {
+		for _, s := range strings.Split(p, "/") {
+			if _, err := path.Match(s, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func globSegments(pattern, names []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if globSegments(pattern[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], names[0]); err != nil || !ok {
+			return false
+		}
+		pattern, names = pattern[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// expandBraces will return the patterns for every alternative of the first {a,b} group, expanded
+// recursively
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+	var alts []string
+	depth, last := 0, start+1
+	for i := start + 1; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 0 {
+				alts = append(alts, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			alts = append(alts, pattern[last:i])
+			var res []string
+			for _, a := range alts {
+				res = append(res, expandBraces(pattern[:start]+a+pattern[i+1:])...)
+			}
+			return res
+		}
+	}
+	// unterminated, matched literally
+	return []string{pattern}
+}
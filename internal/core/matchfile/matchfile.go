@@ -2,6 +2,7 @@ package matchfile
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -11,6 +12,15 @@ type MatchFile struct {
 	Path      string
 	Filename  string
 	Extension string
+	Content   []byte // the content of files that aren't on disk, e.g. the entries of archives
+	read      *readResult
+}
+
+// readResult holds the content of a file read from disk, see Cached
+type readResult struct {
+	data []byte
+	err  error
+	done bool
 }
 
 // New will generate a match object by dissecting a filename
@@ -29,17 +39,49 @@ func New(path string) MatchFile {
 	}
 }
 
+// Read returns the content of the file, from disk unless it was given
+func (f MatchFile) Read() ([]byte, error) {
+	if f.Content != nil {
+		return f.Content, nil
+	}
+	if f.read == nil {
+		return os.ReadFile(f.Path)
+	}
+	if !f.read.done {
+		f.read.data, f.read.err = os.ReadFile(f.Path)
+		f.read.done = true
+	}
+	return f.read.data, f.read.err
+}
+
+// Cached returns a copy of the file which is read from disk at most once, however many times Read is
+// called on it or on its copies. It's not safe for concurrent use.
+func (f MatchFile) Cached() MatchFile {
+	if f.read == nil {
+		f.read = &readResult{}
+	}
+	return f
+}
+
 // IsSkippable will check the matched file against a list of extensions or paths either supplied by the user or set by default
 func (f *MatchFile) IsSkippable(skippableExt, skippablePath []string) bool {
 	ext := strings.ToLower(f.Extension)
-	path := strings.ToLower(f.Path)
 	for _, v := range skippableExt {
 		if ext == fmt.Sprintf(".%s", v) {
 			return true
 		}
 	}
+	return HasSkippablePath(filepath.ToSlash(f.Path), skippablePath)
+}
+
+// HasSkippablePath will check if any of the paths is part of the slash separated path, case insensitive.
+// The paths are matched on whole directories, e.g. vendor/cache is part of /src/vendor/cache/gems and of
+// src/vendor/cache, but not of /src/myvendor/cache or src/vendor/cached.
+func HasSkippablePath(path string, skippablePath []string) bool {
+	path = "/" + strings.Trim(strings.ToLower(path), "/") + "/"
 	for _, v := range skippablePath {
-		if strings.Contains(path, v) {
+		v = strings.Trim(strings.ToLower(v), "/")
+		if v != "" && strings.Contains(path, "/"+v+"/") {
 			return true
 		}
 	}
@@ -1,9 +1,12 @@
 package matchfile
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew(t *testing.T) {
@@ -41,6 +44,11 @@ func TestMatchFile_IsSkippable(t *testing.T) {
 		{"no extension", &MatchFile{Path: "/some/random/path", Filename: "path"}, args{skippableExt: []string{"path"}}, false},
 		{"extension doesn't match", &MatchFile{Path: "/some/random/path.file", Filename: "path", Extension: "file"}, args{skippableExt: []string{"path"}}, false},
 		{"broken path, contains part of it, it's a match", &MatchFile{Path: "&broken^path/haha", Filename: "haha"}, args{skippablePath: []string{"haha"}}, true},
+		{"directory skippable", &MatchFile{Path: "/src/vendor/cache/gems/key.pem", Filename: "key.pem", Extension: ".pem"}, args{skippablePath: []string{"vendor/cache"}}, true},
+		{"directory skippable, case insensitive", &MatchFile{Path: "/src/Node_Modules/pkg/index.js", Filename: "index.js", Extension: ".js"}, args{skippablePath: []string{"node_modules/"}}, true},
+		{"relative path skippable", &MatchFile{Path: "vendor/cache/key.pem", Filename: "key.pem", Extension: ".pem"}, args{skippablePath: []string{"vendor/cache"}}, true},
+		{"part of a directory name", &MatchFile{Path: "/src/myvendor/cache/key.pem", Filename: "key.pem", Extension: ".pem"}, args{skippablePath: []string{"vendor/cache"}}, false},
+		{"prefix of a directory name", &MatchFile{Path: "/src/vendor/cached/key.pem", Filename: "key.pem", Extension: ".pem"}, args{skippablePath: []string{"vendor/cache"}}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,3 +57,64 @@ func TestMatchFile_IsSkippable(t *testing.T) {
 		})
 	}
 }
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.yaml", "app.yaml", true},
+		{"*.yaml", "config/app.yaml", false},
+		{"**/*.yaml", "app.yaml", true},
+		{"**/*.yaml", "deploy/k8s/app.yaml", true},
+		{"**/*.yaml", "deploy/k8s/app.yml", false},
+		{"**/*.{yaml,yml}", "deploy/k8s/app.yml", true},
+		{"deploy/**", "deploy/k8s/app.yaml", true},
+		{"deploy/**", "deploy", true},
+		{"deploy/**", "src/deploy/app.yaml", false},
+		{"**/testdata/**", "pkg/testdata/key.pem", true},
+		{"**/testdata/**", "pkg/testdata", true},
+		{"**/testdata/**", "pkg/testdatas/key.pem", false},
+		{"src/*/main.go", "src/app/main.go", true},
+		{"src/*/main.go", "src/app/cmd/main.go", false},
+		{"src/**/main.go", "src/app/cmd/main.go", true},
+		{"[a-c]?.txt", "b1.txt", true},
+		{"{a,b{c,d}}.txt", "bd.txt", true},
+		{"{a,b{c,d}}.txt", "b.txt", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Glob(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	assert.NoError(t, ValidateGlob("**/*.{yaml,yml}"))
+	assert.Error(t, ValidateGlob("**/[a-"))
+	assert.Error(t, ValidateGlob("*.{yaml"))
+}
+
+func TestMatchFile_Cached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("one"), 0600))
+	mf := New(path).Cached()
+	data, err := mf.Read()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("one"), data)
+
+	// copies share the content read first
+	require.NoError(t, os.WriteFile(path, []byte("two"), 0600))
+	cp := mf
+	data, err = cp.Read()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("one"), data)
+
+	data, err = New(path).Read()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("two"), data)
+
+	_, err = New(filepath.Join(t.TempDir(), "missing")).Cached().Read()
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package privatekey
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var pemBegin = regexp.MustCompile(`-----BEGIN ((?:[A-Z0-9]+ )*PRIVATE KEY)-----`)
+
+// findPEM will return the PEM armored private keys. Keys can be indented, e.g. within YAML block
+// scalars, or written on a single line with escaped newlines, e.g. within JSON strings or source code.
+func findPEM(s string) []Key {
+	var res []Key
+	for offset := 0; offset < len(s); {
+		loc := pemBegin.FindStringSubmatchIndex(s[offset:])
+		if loc == nil {
+			break
+		}
+		start := offset + loc[0]
+		end := strings.Index(s[start:], "-----END "+s[offset+loc[2]:offset+loc[3]]+"-----")
+		if end < 0 {
+			break
+		}
+		end += start + len("-----END -----") + loc[3] - loc[2]
+		offset = end
+
+		text := s[start:end]
+		key, err := parsePEM(text)
+		if err != nil {
+			continue
+		}
+		key.Text = text
+		key.StartLine = lineAt(s, start)
+		key.EndLine = lineAt(s, end)
+		res = append(res, key)
+	}
+	return res
+}
+
+// parsePEM will parse a private key. Encrypted keys are only checked to be well-formed, as their content
+// can't be read without the passphrase.
+func parsePEM(text string) (Key, error) {
+	if !strings.Contains(text, "\n") {
+		text = strings.ReplaceAll(text, `\n`, "\n")
+	}
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	data := []byte(strings.Join(lines, "\n") + "\n")
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("invalid PEM block")
+	}
+
+	key := Key{Format: FormatPEM}
+	switch {
+	case block.Type == "OPENSSH PRIVATE KEY":
+		key.Format = FormatOpenSSH
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		// PKCS#8 EncryptedPrivateKeyInfo, the algorithm and the encrypted key
+		var info struct {
+			Algorithm asn1.RawValue
+			Data      []byte
+		}
+		if rest, err := asn1.Unmarshal(block.Bytes, &info); err != nil || len(rest) > 0 || len(info.Data) == 0 {
+			return Key{}, errors.New("invalid encrypted PKCS#8 key")
+		}
+		key.Encrypted = true
+		return key, nil
+	case strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED"):
+		if block.Headers["DEK-Info"] == "" || len(block.Bytes) == 0 || len(block.Bytes)%8 != 0 {
+			return Key{}, errors.New("invalid encrypted PEM key")
+		}
+		key.Type = pemKeyType(block.Type)
+		key.Encrypted = true
+		return key, nil
+	}
+
+	raw, err := ssh.ParseRawPrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missing):
+		key.Encrypted = true
+		if missing.PublicKey != nil {
+			key.Type = sshKeyType(missing.PublicKey.Type())
+			key.Fingerprint = ssh.FingerprintSHA256(missing.PublicKey)
+		}
+		return key, nil
+	case err != nil:
+		return Key{}, err
+	}
+	key.Type, key.Fingerprint = describe(raw)
+	return key, nil
+}
+
+// pemKeyType returns the key type of the PEM label of legacy keys
+func pemKeyType(label string) string {
+	switch label {
+	case "RSA PRIVATE KEY":
+		return TypeRSA
+	case "EC PRIVATE KEY":
+		return TypeECDSA
+	case "DSA PRIVATE KEY":
+		return TypeDSA
+	}
+	return ""
+}
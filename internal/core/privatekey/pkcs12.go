@@ -0,0 +1,120 @@
+package privatekey
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+)
+
+// PKCS#7 content types and PKCS#12 bag types, RFC 7292
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidKeyBag        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidShroudedBag   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Cert      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue   `asn1:"tag:0,explicit"`
+	Attributes []asn1.RawValue `asn1:"set,optional"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+// parsePKCS12 will read the structure of a PKCS#12 keystore without its password. Keys are commonly
+// shrouded, i.e. encrypted, within the keystore; their fingerprint is then taken from the certificate
+// stored next to them, if it isn't encrypted as well. Keystores without keys, e.g. trust stores, aren't
+// reported.
+func parsePKCS12(data []byte) (Key, bool) {
+	if len(data) == 0 || data[0] != 0x30 {
+		return Key{}, false
+	}
+	var pfx pfxPdu
+	if rest, err := asn1.Unmarshal(data, &pfx); err != nil || len(rest) > 0 || pfx.Version != 3 {
+		return Key{}, false
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidData) {
+		// public-key integrity mode, signed by a certificate, isn't supported
+		return Key{}, false
+	}
+	var authSafe []contentInfo
+	if err := unmarshalOctets(pfx.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return Key{}, false
+	}
+
+	key := Key{Format: FormatPKCS12, StartLine: 1, EndLine: 1}
+	var hasKey, plainKey, hasEncrypted bool
+	var certFingerprint string
+	for _, ci := range authSafe {
+		switch {
+		case ci.ContentType.Equal(oidEncryptedData):
+			hasEncrypted = true
+		case ci.ContentType.Equal(oidData):
+			var bags []safeBag
+			if err := unmarshalOctets(ci.Content.Bytes, &bags); err != nil {
+				return Key{}, false
+			}
+			for _, bag := range bags {
+				switch {
+				case bag.ID.Equal(oidKeyBag):
+					raw, err := x509.ParsePKCS8PrivateKey(bag.Value.Bytes)
+					if err != nil {
+						return Key{}, false
+					}
+					hasKey, plainKey = true, true
+					key.Type, key.Fingerprint = describe(raw)
+				case bag.ID.Equal(oidShroudedBag):
+					hasKey = true
+				case bag.ID.Equal(oidCertBag):
+					var cb certBag
+					if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil || !cb.ID.Equal(oidX509Cert) {
+						continue
+					}
+					var der []byte
+					if _, err := asn1.Unmarshal(cb.Data, &der); err != nil {
+						continue
+					}
+					if cert, err := x509.ParseCertificate(der); err == nil && certFingerprint == "" {
+						certFingerprint = Fingerprint(cert.PublicKey)
+					}
+				}
+			}
+		}
+	}
+
+	if !hasKey && !hasEncrypted {
+		return Key{}, false
+	}
+	// the keys can be stored within the encrypted content as well
+	key.Encrypted = !plainKey
+	if key.Fingerprint == "" {
+		key.Fingerprint = certFingerprint
+	}
+	return key, true
+}
+
+// unmarshalOctets will parse the DER encoded content of an OCTET STRING
+func unmarshalOctets(data []byte, v interface{}) error {
+	var octets []byte
+	if _, err := asn1.Unmarshal(data, &octets); err != nil {
+		return err
+	}
+	_, err := asn1.Unmarshal(octets, v)
+	return err
+}
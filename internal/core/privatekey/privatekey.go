@@ -0,0 +1,145 @@
+// Package privatekey finds private keys in PEM, OpenSSH, PuTTY and PKCS#12 formats. Keys are parsed
+// to confirm that they are well-formed, so that placeholders and truncated examples aren't reported,
+// and identified by the fingerprint of their public key, so that the same key can be correlated
+// wherever it leaked.
+package privatekey
+
+import (
+	"crypto"
+	"crypto/dsa" //nolint:staticcheck // DSA keys are still found in the wild
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Supported formats
+const (
+	FormatPEM     = "pem"     // PKCS#1, PKCS#8, SEC1 and DSA keys
+	FormatOpenSSH = "openssh" // the PEM armored format of ssh-keygen
+	FormatPuTTY   = "putty"   // PuTTY .ppk files
+	FormatPKCS12  = "pkcs12"  // .p12 and .pfx keystores
+)
+
+// Key types
+const (
+	TypeRSA     = "rsa"
+	TypeECDSA   = "ecdsa"
+	TypeEd25519 = "ed25519"
+	TypeDSA     = "dsa"
+)
+
+// Key is a private key found in a file
+type Key struct {
+	Format      string
+	Type        string // empty when the key is encrypted and its type isn't stored in clear
+	Fingerprint string // SHA256 of the public key, empty when it can't be derived without the passphrase
+	Text        string // the key as found in the file, empty for binary keystores
+	StartLine   int    // 1-based lines of the first and last line of the key
+	EndLine     int
+	Encrypted   bool
+}
+
+// ID returns what identifies the key, its fingerprint when known and its text otherwise
+func (k Key) ID() string {
+	if k.Fingerprint != "" {
+		return k.Fingerprint
+	}
+	return k.Text
+}
+
+// String describes the key, e.g. "rsa openssh, encrypted"
+func (k Key) String() string {
+	s := k.Format
+	if k.Type != "" {
+		s = k.Type + " " + s
+	}
+	if k.Encrypted {
+		s += ", encrypted"
+	}
+	return s
+}
+
+// IsKeystore returns true if the file is expected to be a binary keystore, based on its name
+func IsKeystore(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".p12", ".pfx":
+		return true
+	}
+	return false
+}
+
+// Find will return the valid private keys in data, ordered by their position. Blocks that look like keys
+// but can't be parsed are skipped.
+func Find(data []byte) []Key {
+	if key, ok := parsePKCS12(data); ok {
+		return []Key{key}
+	}
+	keys := append(findPEM(string(data)), findPuTTY(string(data))...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].StartLine < keys[j].StartLine
+	})
+	return keys
+}
+
+// Fingerprint returns the SHA256 fingerprint of the public key, in the format of ssh-keygen -l. Keys that
+// can't be used with SSH are hashed in their PKIX form.
+func Fingerprint(pub crypto.PublicKey) string {
+	if sshPub, err := ssh.NewPublicKey(pub); err == nil {
+		return ssh.FingerprintSHA256(sshPub)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// describe returns the type and fingerprint of a parsed private key
+func describe(key interface{}) (string, string) {
+	var pub crypto.PublicKey
+	var keyType string
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		keyType, pub = TypeRSA, k.Public()
+	case *ecdsa.PrivateKey:
+		keyType, pub = TypeECDSA, k.Public()
+	case ed25519.PrivateKey:
+		keyType, pub = TypeEd25519, k.Public()
+	case *ed25519.PrivateKey:
+		keyType, pub = TypeEd25519, k.Public()
+	case *dsa.PrivateKey:
+		keyType, pub = TypeDSA, &k.PublicKey
+	default:
+		return "", ""
+	}
+	return keyType, Fingerprint(pub)
+}
+
+// sshKeyType returns the key type of an SSH public key algorithm, e.g. ssh-rsa
+func sshKeyType(algo string) string {
+	switch {
+	case algo == ssh.KeyAlgoRSA:
+		return TypeRSA
+	case algo == ssh.KeyAlgoDSA:
+		return TypeDSA
+	case algo == ssh.KeyAlgoED25519:
+		return TypeEd25519
+	case strings.HasPrefix(algo, "ecdsa-sha2-"):
+		return TypeECDSA
+	}
+	return algo
+}
+
+// lineAt returns the 1-based line of the offset
+func lineAt(s string, offset int) int {
+	return strings.Count(s[:offset], "\n") + 1
+}
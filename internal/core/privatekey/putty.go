@@ -0,0 +1,84 @@
+package privatekey
+
+import (
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var puttyHeader = regexp.MustCompile(`(?m)^PuTTY-User-Key-File-[23]: *(\S+)[ \t]*\r?$`)
+
+// findPuTTY will return the keys of PuTTY .ppk files, version 2 and 3. The public key is stored in clear,
+// so the fingerprint is known even when the private key is encrypted.
+func findPuTTY(s string) []Key {
+	var res []Key
+	for _, loc := range puttyHeader.FindAllStringSubmatchIndex(s, -1) {
+		end, key, err := parsePuTTY(s, loc[0], s[loc[2]:loc[3]])
+		if err != nil {
+			continue
+		}
+		key.Text = s[loc[0]:end]
+		key.StartLine = lineAt(s, loc[0])
+		key.EndLine = lineAt(s, end)
+		res = append(res, key)
+	}
+	return res
+}
+
+// parsePuTTY will read the fields of the key starting at offset, up to its Private-MAC. Returns the offset
+// of the end of the key.
+func parsePuTTY(s string, offset int, algo string) (int, Key, error) {
+	key := Key{Format: FormatPuTTY, Type: sshKeyType(algo)}
+	lines := strings.SplitAfter(s[offset:], "\n")
+	var public, private []byte
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return 0, Key{}, errors.New("invalid PuTTY key, unexpected line")
+		}
+
+		var err error
+		switch name {
+		case "Encryption":
+			key.Encrypted = value != "none"
+		case "Public-Lines":
+			public, i, err = puttyBlob(lines, i, value)
+		case "Private-Lines":
+			private, i, err = puttyBlob(lines, i, value)
+		case "Private-MAC", "Private-Hash":
+			if len(public) == 0 || len(private) == 0 {
+				return 0, Key{}, errors.New("invalid PuTTY key, missing public or private key")
+			}
+			pub, err := ssh.ParsePublicKey(public)
+			if err != nil {
+				return 0, Key{}, err
+			}
+			key.Fingerprint = ssh.FingerprintSHA256(pub)
+			return offset + len(strings.Join(lines[:i], "")) + len(strings.TrimRight(lines[i], "\r\n")), key, nil
+		}
+		if err != nil {
+			return 0, Key{}, err
+		}
+	}
+	return 0, Key{}, errors.New("invalid PuTTY key, missing Private-MAC")
+}
+
+// puttyBlob will decode the given number of base64 lines following line i. Returns the index of the
+// last line of the blob.
+func puttyBlob(lines []string, i int, count string) ([]byte, int, error) {
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 || i+n >= len(lines) {
+		return nil, i, errors.New("invalid PuTTY key, bad number of lines")
+	}
+	var sb strings.Builder
+	for _, l := range lines[i+1 : i+1+n] {
+		sb.WriteString(strings.TrimSpace(l))
+	}
+	blob, err := base64.StdEncoding.DecodeString(sb.String())
+	return blob, i + n, err
+}
@@ -0,0 +1,231 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+const (
+	Address = "https://dev.azure.com"
+	// apiVersion is supported by Azure DevOps Services, and Azure DevOps Server 2020 and later
+	apiVersion = "6.0"
+	pageSize   = 100
+	// CloneUsername is the username for cloning with a personal access token, any non-empty one is accepted
+	CloneUsername = "pat"
+)
+
+// Client holds an Azure DevOps api client instance
+type Client struct {
+	apiClient *httpapi.Client
+	baseURL   string
+}
+
+type connectionData struct {
+	InstanceID string `json:"instanceId"`
+}
+
+type project struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Project       project `json:"project"`
+	DefaultBranch string  `json:"defaultBranch"`
+	RemoteURL     string  `json:"remoteUrl"`
+	SSHURL        string  `json:"sshUrl"`
+	WebURL        string  `json:"webUrl"`
+	IsDisabled    bool    `json:"isDisabled"`
+	IsFork        bool    `json:"isFork"`
+}
+
+// NewClient creates an Azure DevOps API client instance with a personal access token, for Azure DevOps
+// Services unless the address of an Azure DevOps Server is given, whose collections are the organizations
+func NewClient(token, baseURL string) (*Client, error) {
+	if token == "" {
+		return nil, errors.New("Azure DevOps token is not set")
+	}
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("unable to parse --azure-devops-url: %q", baseURL)
+		}
+	}
+	return &Client{apiClient: httpapi.New(httpapi.BasicAuth("", token)), baseURL: WebURL(baseURL)}, nil
+}
+
+// WebURL returns the address of the instance with the given base URL, dev.azure.com when it's empty
+func WebURL(baseURL string) string {
+	if baseURL == "" {
+		return Address
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// Links returns the web addresses of the repository, and of the file and the commit in it. The owner is
+// the organization and the project of the repository, as in contoso/Web.
+func Links(baseURL, owner, name, commit, path string) (repoURL, fileURL, commitURL string) {
+	repoURL = repositoryURL(baseURL, owner, name)
+	fileURL = fmt.Sprintf("%s?path=%s&version=GC%s", repoURL, queryPath(path), commit)
+	return repoURL, fileURL, fmt.Sprintf("%s/commit/%s", repoURL, commit)
+}
+
+// RawURL returns the address of the content of the file at the commit
+func RawURL(baseURL, owner, name, commit, path string) string {
+	return fmt.Sprintf("%s/%s/_apis/git/repositories/%s/items?path=%s&versionDescriptor.version=%s&versionDescriptor.versionType=commit&download=true&api-version=%s",
+		WebURL(baseURL), escapeOwner(owner), url.PathEscape(name), queryPath(path), commit, apiVersion)
+}
+
+// GetUserOrganization is used to enumerate the organization, or a project in it given as org/project.
+// Azure DevOps has no users owning repositories.
+func (c *Client) GetUserOrganization(ctx context.Context, login string) (*_coreapi.Owner, error) {
+	org, name, isProject := strings.Cut(strings.Trim(login, "/"), "/")
+	if !isProject {
+		var data connectionData
+		if _, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/%s/_apis/connectionData", c.baseURL, url.PathEscape(org)), &data); err != nil {
+			if errors.Is(err, httpapi.ErrNotFound) {
+				return nil, fmt.Errorf("no Azure DevOps organization %s was found", login)
+			}
+			return nil, err
+		}
+		return c.owner(org, data.InstanceID, org, ""), nil
+	}
+	var p project
+	if _, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/%s/_apis/projects/%s?api-version=%s", c.baseURL, url.PathEscape(org), url.PathEscape(name), apiVersion), &p); err != nil {
+		if errors.Is(err, httpapi.ErrNotFound) {
+			return nil, fmt.Errorf("no Azure DevOps project %s was found", login)
+		}
+		return nil, err
+	}
+	return c.owner(org+"/"+p.Name, p.ID, p.Name, p.Description), nil
+}
+
+// GetOrganizationMembers returns no members, as the repositories of Azure DevOps belong to projects and
+// all of them are scanned with the organization
+func (c *Client) GetOrganizationMembers(_ context.Context, _ _coreapi.Owner) ([]*_coreapi.Owner, error) {
+	return nil, nil
+}
+
+// GetRepositoriesFromOwner is used gather all the repos of the projects of the organization, or of the project
+func (c *Client) GetRepositoriesFromOwner(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Repository, error) {
+	if target.Login == nil {
+		return nil, errors.New("Login field is not present")
+	}
+	org, name, isProject := strings.Cut(*target.Login, "/")
+	projects := []string{name}
+	if !isProject {
+		var err error
+		projects, err = c.projects(ctx, org)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var repos []*_coreapi.Repository
+	for _, p := range projects {
+		var res struct {
+			Value []repository `json:"value"`
+		}
+		_, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/%s/%s/_apis/git/repositories?api-version=%s", c.baseURL, url.PathEscape(org), url.PathEscape(p), apiVersion), &res)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res.Value {
+			//don't capture forks, disabled repositories can't be cloned and empty ones have no default branch
+			if r.IsFork || r.IsDisabled || r.DefaultBranch == "" {
+				continue
+			}
+			owner := org + "/" + r.Project.Name
+			repos = append(repos, &_coreapi.Repository{
+				Owner:         owner,
+				ID:            *httpapi.HashID(r.ID),
+				Name:          r.Name,
+				FullName:      owner + "/" + r.Name,
+				CloneURL:      cloneURL(r.RemoteURL),
+				SSHURL:        r.SSHURL,
+				URL:           r.WebURL,
+				DefaultBranch: strings.TrimPrefix(r.DefaultBranch, "refs/heads/"),
+				Description:   r.Project.Description,
+			})
+		}
+	}
+	return repos, nil
+}
+
+// projects returns the names of the projects of the organization, the pages are chained by the
+// continuation token of the previous one
+func (c *Client) projects(ctx context.Context, org string) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		var res struct {
+			Value []project `json:"value"`
+		}
+		u := fmt.Sprintf("%s/%s/_apis/projects?$top=%d&api-version=%s", c.baseURL, url.PathEscape(org), pageSize, apiVersion)
+		if token != "" {
+			u += "&continuationToken=" + url.QueryEscape(token)
+		}
+		h, err := c.apiClient.Get(ctx, u, &res)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range res.Value {
+			names = append(names, p.Name)
+		}
+		token = h.Get("X-MS-ContinuationToken")
+		if token == "" || len(res.Value) == 0 {
+			return names, nil
+		}
+	}
+}
+
+func (c *Client) owner(login, id, name, description string) *_coreapi.Owner {
+	org, project, _ := strings.Cut(login, "/")
+	webURL := fmt.Sprintf("%s/%s", c.baseURL, url.PathEscape(org))
+	if project != "" {
+		webURL += "/" + url.PathEscape(project)
+	}
+	return &_coreapi.Owner{
+		Login: util.StringToPointer(login),
+		ID:    httpapi.HashID(id),
+		Kind:  util.StringToPointer(_coreapi.TargetTypeOrganization),
+		Type:  util.StringToPointer(_coreapi.TargetTypeOrganization),
+		Name:  util.StringToPointer(name),
+		URL:   util.StringToPointer(webURL),
+		Bio:   util.StringToPointer(description),
+	}
+}
+
+func repositoryURL(baseURL, owner, name string) string {
+	return fmt.Sprintf("%s/%s/_git/%s", WebURL(baseURL), escapeOwner(owner), url.PathEscape(name))
+}
+
+// escapeOwner escapes the organization and the project of the owner, project names can have spaces
+func escapeOwner(owner string) string {
+	org, project, _ := strings.Cut(owner, "/")
+	return url.PathEscape(org) + "/" + url.PathEscape(project)
+}
+
+// queryPath returns the path of a file in the repository as the path query parameter, which starts with a slash
+func queryPath(path string) string {
+	return strings.ReplaceAll(url.QueryEscape("/"+path), "%2F", "/")
+}
+
+// cloneURL returns the clone address without the organization as the user, so that the token is used
+func cloneURL(remoteURL string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return remoteURL
+	}
+	u.User = nil
+	return u.String()
+}
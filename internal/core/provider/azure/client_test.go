@@ -0,0 +1,121 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stub serves the JSON responses by path and query with the continuation token of the paged ones, and 404
+// for anything else
+func stub(t *testing.T, responses map[string]interface{}, tokens map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "", user)
+		assert.Equal(t, "secret", pass)
+		key := r.URL.Path
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		res, ok := responses[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if token, ok := tokens[key]; ok {
+			w.Header().Set("X-MS-ContinuationToken", token)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("secret", "")
+	require.NoError(t, err)
+	assert.Equal(t, Address, c.baseURL)
+	c, err = NewClient("secret", "https://tfs.example.com/tfs/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://tfs.example.com/tfs", c.baseURL)
+
+	_, err = NewClient("", "")
+	assert.EqualError(t, err, "Azure DevOps token is not set")
+	_, err = NewClient("secret", "tfs.example.com")
+	assert.EqualError(t, err, `unable to parse --azure-devops-url: "tfs.example.com"`)
+}
+
+func TestLinks(t *testing.T) {
+	repo, file, commit := Links("", "contoso/Web Apps", "api", "abc", "config/app settings.json")
+	assert.Equal(t, "https://dev.azure.com/contoso/Web%20Apps/_git/api", repo)
+	assert.Equal(t, "https://dev.azure.com/contoso/Web%20Apps/_git/api?path=/config/app+settings.json&version=GCabc", file)
+	assert.Equal(t, "https://dev.azure.com/contoso/Web%20Apps/_git/api/commit/abc", commit)
+	assert.Equal(t, "https://tfs.example.com/tfs/contoso/Web%20Apps/_apis/git/repositories/api/items?path=/config/app.json&versionDescriptor.version=abc&versionDescriptor.versionType=commit&download=true&api-version=6.0",
+		RawURL("https://tfs.example.com/tfs/", "contoso/Web Apps", "api", "abc", "config/app.json"))
+}
+
+func TestClient(t *testing.T) {
+	repo := func(id, project, name string, extra map[string]interface{}) map[string]interface{} {
+		r := map[string]interface{}{"id": id, "name": name, "project": map[string]string{"id": project, "name": project},
+			"remoteUrl": "https://contoso@dev.azure.com/contoso/" + project + "/_git/" + name, "sshUrl": "git@ssh.dev.azure.com:v3/contoso/" + project + "/" + name, "webUrl": "https://dev.azure.com/contoso/" + project + "/_git/" + name,
+			"defaultBranch": "refs/heads/main"}
+		for k, v := range extra {
+			r[k] = v
+		}
+		return r
+	}
+	srv := stub(t, map[string]interface{}{
+		"/contoso/_apis/connectionData":                                           map[string]string{"instanceId": "org-guid"},
+		"/contoso/_apis/projects/Web?api-version=6.0":                             map[string]string{"id": "web-guid", "name": "Web", "description": "Sites"},
+		"/contoso/_apis/projects?$top=100&api-version=6.0":                        map[string]interface{}{"value": []map[string]string{{"name": "Web"}}},
+		"/contoso/_apis/projects?$top=100&api-version=6.0&continuationToken=next": map[string]interface{}{"value": []map[string]string{{"name": "Ops"}}},
+		"/contoso/Web/_apis/git/repositories?api-version=6.0":                     map[string]interface{}{"value": []interface{}{repo("1", "Web", "api", nil), repo("2", "Web", "api-fork", map[string]interface{}{"isFork": true})}},
+		"/contoso/Ops/_apis/git/repositories?api-version=6.0":                     map[string]interface{}{"value": []interface{}{repo("3", "Ops", "old", map[string]interface{}{"isDisabled": true}), repo("4", "Ops", "empty", map[string]interface{}{"defaultBranch": ""}), repo("5", "Ops", "infra", nil)}},
+	}, map[string]string{"/contoso/_apis/projects?$top=100&api-version=6.0": "next"})
+	c, err := NewClient("secret", srv.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	org, err := c.GetUserOrganization(ctx, "contoso")
+	require.NoError(t, err)
+	assert.Equal(t, "contoso", *org.Login)
+	assert.Equal(t, httpapi.HashID("org-guid"), org.ID)
+	assert.Equal(t, _coreapi.TargetTypeOrganization, *org.Kind)
+	assert.Equal(t, srv.URL+"/contoso", *org.URL)
+
+	project, err := c.GetUserOrganization(ctx, "contoso/Web")
+	require.NoError(t, err)
+	assert.Equal(t, "contoso/Web", *project.Login)
+	assert.Equal(t, "Sites", *project.Bio)
+	assert.Equal(t, srv.URL+"/contoso/Web", *project.URL)
+
+	_, err = c.GetUserOrganization(ctx, "fabrikam")
+	assert.EqualError(t, err, "no Azure DevOps organization fabrikam was found")
+	_, err = c.GetUserOrganization(ctx, "contoso/Nope")
+	assert.EqualError(t, err, "no Azure DevOps project contoso/Nope was found")
+
+	members, err := c.GetOrganizationMembers(ctx, *org)
+	require.NoError(t, err)
+	assert.Empty(t, members)
+
+	api := &_coreapi.Repository{Owner: "contoso/Web", ID: *httpapi.HashID("1"), Name: "api", FullName: "contoso/Web/api", CloneURL: "https://dev.azure.com/contoso/Web/_git/api", SSHURL: "git@ssh.dev.azure.com:v3/contoso/Web/api", URL: "https://dev.azure.com/contoso/Web/_git/api", DefaultBranch: "main"}
+	// the projects of the organization are read until there is no continuation token
+	repos, err := c.GetRepositoriesFromOwner(ctx, *org)
+	require.NoError(t, err)
+	assert.Equal(t, []*_coreapi.Repository{
+		api,
+		{Owner: "contoso/Ops", ID: *httpapi.HashID("5"), Name: "infra", FullName: "contoso/Ops/infra", CloneURL: "https://dev.azure.com/contoso/Ops/_git/infra", SSHURL: "git@ssh.dev.azure.com:v3/contoso/Ops/infra", URL: "https://dev.azure.com/contoso/Ops/_git/infra", DefaultBranch: "main"},
+	}, repos)
+
+	repos, err = c.GetRepositoriesFromOwner(ctx, *project)
+	require.NoError(t, err)
+	assert.Equal(t, []*_coreapi.Repository{api}, repos)
+}
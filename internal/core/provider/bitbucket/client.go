@@ -0,0 +1,91 @@
+package bitbucket
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+)
+
+const (
+	Address  = "https://bitbucket.org"
+	cloudAPI = "https://api.bitbucket.org/2.0"
+	// tokenUsername is the username for cloning with access tokens, which don't belong to a user
+	tokenUsername = "x-token-auth"
+)
+
+// NewClient creates a Bitbucket API client instance, for Bitbucket Cloud unless the address of a Bitbucket
+// Server or Data Center instance is given. With a username the token is an app password, or a personal
+// access token of Bitbucket Server, otherwise it's an access token.
+func NewClient(token, username, baseURL string) (api.IClient, error) {
+	if token == "" {
+		return nil, errors.New("Bitbucket token is not set")
+	}
+	auth := httpapi.BearerAuth(token)
+	if username != "" {
+		auth = httpapi.BasicAuth(username, token)
+	}
+	if IsCloud(baseURL) {
+		return &CloudClient{apiClient: httpapi.New(auth), apiURL: cloudAPI}, nil
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("unable to parse --bitbucket-url: %q", baseURL)
+	}
+	return &ServerClient{apiClient: httpapi.New(auth), apiURL: WebURL(baseURL) + "/rest/api/1.0"}, nil
+}
+
+// IsCloud reports whether the base URL is Bitbucket Cloud, which it is when it's empty
+func IsCloud(baseURL string) bool {
+	if baseURL == "" {
+		return true
+	}
+	u, err := url.Parse(baseURL)
+	return err == nil && (u.Host == "bitbucket.org" || u.Host == "api.bitbucket.org")
+}
+
+// WebURL returns the address of the instance with the given base URL, bitbucket.org when it's empty
+func WebURL(baseURL string) string {
+	if IsCloud(baseURL) {
+		return Address
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// CloneUsername returns the username to clone with, given the one the token belongs to
+func CloneUsername(username string) string {
+	if username == "" {
+		return tokenUsername
+	}
+	return username
+}
+
+// Links returns the web addresses of the repository, and of the file and the commit in it. The owner is the
+// workspace for Bitbucket Cloud, and the project key for Bitbucket Server, with personal projects starting
+// with a ~.
+func Links(baseURL, owner, name, commit, path string) (repoURL, fileURL, commitURL string) {
+	if IsCloud(baseURL) {
+		repoURL = fmt.Sprintf("%s/%s/%s", Address, owner, name)
+		return repoURL, fmt.Sprintf("%s/src/%s/%s", repoURL, commit, path), fmt.Sprintf("%s/commits/%s", repoURL, commit)
+	}
+	repoURL = serverRepoURL(baseURL, owner, name)
+	return repoURL, fmt.Sprintf("%s/browse/%s?at=%s", repoURL, path, commit), fmt.Sprintf("%s/commits/%s", repoURL, commit)
+}
+
+// RawURL returns the address of the content of the file at the commit
+func RawURL(baseURL, owner, name, commit, path string) string {
+	if IsCloud(baseURL) {
+		return fmt.Sprintf("%s/%s/%s/raw/%s/%s", Address, owner, name, commit, path)
+	}
+	return fmt.Sprintf("%s/raw/%s?at=%s", serverRepoURL(baseURL, owner, name), path, commit)
+}
+
+func serverRepoURL(baseURL, owner, name string) string {
+	if strings.HasPrefix(owner, "~") {
+		return fmt.Sprintf("%s/users/%s/repos/%s", WebURL(baseURL), strings.ToLower(owner[1:]), name)
+	}
+	return fmt.Sprintf("%s/projects/%s/repos/%s", WebURL(baseURL), owner, name)
+}
@@ -0,0 +1,201 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stub serves the JSON responses by path and query, and 404 for anything else
+func stub(t *testing.T, responses map[string]interface{}) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		key := r.URL.Path
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		res, ok := responses[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("token", "", "")
+	assert.NoError(t, err)
+	assert.IsType(t, &CloudClient{}, c)
+
+	c, err = NewClient("token", "jane", "https://bitbucket.example.com/")
+	assert.NoError(t, err)
+	assert.IsType(t, &ServerClient{}, c)
+	assert.Equal(t, "https://bitbucket.example.com/rest/api/1.0", c.(*ServerClient).apiURL)
+
+	_, err = NewClient("", "", "")
+	assert.EqualError(t, err, "Bitbucket token is not set")
+	_, err = NewClient("token", "", "bitbucket.example.com")
+	assert.Error(t, err)
+}
+
+func TestLinks(t *testing.T) {
+	tests := []struct {
+		name, baseURL, owner                    string
+		wantRepo, wantFile, wantCommit, wantRaw string
+	}{
+		{"cloud", "", "acme", "https://bitbucket.org/acme/api", "https://bitbucket.org/acme/api/src/abc/config/app.yaml", "https://bitbucket.org/acme/api/commits/abc", "https://bitbucket.org/acme/api/raw/abc/config/app.yaml"},
+		{"server", "https://bb.example.com/", "PLAT", "https://bb.example.com/projects/PLAT/repos/api", "https://bb.example.com/projects/PLAT/repos/api/browse/config/app.yaml?at=abc", "https://bb.example.com/projects/PLAT/repos/api/commits/abc", "https://bb.example.com/projects/PLAT/repos/api/raw/config/app.yaml?at=abc"},
+		{"server, personal project", "https://bb.example.com", "~JANE", "https://bb.example.com/users/jane/repos/api", "https://bb.example.com/users/jane/repos/api/browse/config/app.yaml?at=abc", "https://bb.example.com/users/jane/repos/api/commits/abc", "https://bb.example.com/users/jane/repos/api/raw/config/app.yaml?at=abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, file, commit := Links(tt.baseURL, tt.owner, "api", "abc", "config/app.yaml")
+			assert.Equal(t, tt.wantRepo, repo)
+			assert.Equal(t, tt.wantFile, file)
+			assert.Equal(t, tt.wantCommit, commit)
+			assert.Equal(t, tt.wantRaw, RawURL(tt.baseURL, tt.owner, "api", "abc", "config/app.yaml"))
+		})
+	}
+}
+
+func TestCloneUsername(t *testing.T) {
+	assert.Equal(t, "x-token-auth", CloneUsername(""))
+	assert.Equal(t, "jane", CloneUsername("jane"))
+}
+
+func TestCloudClient(t *testing.T) {
+	user := map[string]interface{}{"uuid": "{u-1}", "display_name": "Jane", "links": map[string]interface{}{"html": map[string]string{"href": "https://bitbucket.org/%7Bu-1%7D/"}}}
+	srv := stub(t, map[string]interface{}{
+		"/workspaces/acme":                     map[string]interface{}{"uuid": "{w-1}", "slug": "acme", "name": "Acme"},
+		"/users/{u-1}":                         user,
+		"/workspaces/acme/members?pagelen=100": map[string]interface{}{"values": []interface{}{map[string]interface{}{"user": user}}},
+		"/repositories/acme?pagelen=100": map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{
+					"uuid": "{r-1}", "slug": "api", "full_name": "acme/api", "mainbranch": map[string]string{"name": "main"},
+					"workspace": map[string]string{"slug": "acme"},
+					"links": map[string]interface{}{
+						"html":  map[string]string{"href": "https://bitbucket.org/acme/api"},
+						"clone": []map[string]string{{"name": "https", "href": "https://jane@bitbucket.org/acme/api.git"}, {"name": "ssh", "href": "git@bitbucket.org:acme/api.git"}},
+					},
+				},
+				map[string]interface{}{"uuid": "{r-2}", "slug": "api-fork", "parent": map[string]string{"full_name": "other/api"}},
+			},
+			"next": "NEXT",
+		},
+		"/repositories/acme?pagelen=100&page=2": map[string]interface{}{
+			"values": []interface{}{map[string]interface{}{"uuid": "{r-3}", "slug": "empty", "workspace": map[string]string{"slug": "acme"}}},
+		},
+	})
+	// the next links are absolute
+	srv.Config.Handler = rewriteNext(srv.Config.Handler, "NEXT", srv.URL+"/repositories/acme?pagelen=100&page=2")
+	c := &CloudClient{apiClient: httpapi.New(httpapi.BearerAuth("token")), apiURL: srv.URL}
+	ctx := context.Background()
+
+	ws, err := c.GetUserOrganization(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", *ws.Login)
+	assert.Equal(t, _coreapi.TargetTypeOrganization, *ws.Kind)
+	assert.Equal(t, httpapi.HashID("{w-1}"), ws.ID)
+
+	u, err := c.GetUserOrganization(ctx, "{u-1}")
+	require.NoError(t, err)
+	assert.Equal(t, "{u-1}", *u.Login)
+	assert.Equal(t, "Jane", *u.Name)
+	assert.Equal(t, _coreapi.TargetTypeUser, *u.Kind)
+
+	_, err = c.GetUserOrganization(ctx, "nobody")
+	assert.EqualError(t, err, "no Bitbucket workspace or user nobody was found. Users are looked up by their UUID or account ID")
+
+	members, err := c.GetOrganizationMembers(ctx, *ws)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "{u-1}", *members[0].Login)
+
+	repos, err := c.GetRepositoriesFromOwner(ctx, *ws)
+	require.NoError(t, err)
+	assert.Equal(t, []*_coreapi.Repository{
+		{Owner: "acme", ID: *httpapi.HashID("{r-1}"), Name: "api", FullName: "acme/api", CloneURL: "https://bitbucket.org/acme/api.git", SSHURL: "git@bitbucket.org:acme/api.git", URL: "https://bitbucket.org/acme/api", DefaultBranch: "main"},
+		{Owner: "acme", ID: *httpapi.HashID("{r-3}"), Name: "empty"},
+	}, repos)
+}
+
+func TestServerClient(t *testing.T) {
+	project := map[string]interface{}{"id": 7, "key": "PLAT", "name": "Platform", "links": map[string]interface{}{"self": []map[string]string{{"href": "https://bb.example.com/projects/PLAT"}}}}
+	user := map[string]interface{}{"id": 3, "slug": "jane", "displayName": "Jane"}
+	srv := stub(t, map[string]interface{}{
+		"/rest/api/1.0/projects/PLAT":                                     project,
+		"/rest/api/1.0/users/jane":                                        user,
+		"/rest/api/1.0/projects/PLAT/permissions/users?limit=100&start=0": map[string]interface{}{"values": []interface{}{map[string]interface{}{"user": user}}, "isLastPage": true},
+		"/rest/api/1.0/projects/PLAT/repos?limit=100&start=0": map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{"id": 11, "slug": "api", "project": project, "links": map[string]interface{}{
+					"self":  []map[string]string{{"href": "https://bb.example.com/projects/PLAT/repos/api/browse"}},
+					"clone": []map[string]string{{"name": "ssh", "href": "ssh://git@bb.example.com:7999/plat/api.git"}, {"name": "http", "href": "https://jane@bb.example.com/scm/plat/api.git"}},
+				}},
+				map[string]interface{}{"id": 12, "slug": "fork", "project": project, "origin": map[string]interface{}{"id": 11}},
+			},
+			"isLastPage": false, "nextPageStart": 2,
+		},
+		"/rest/api/1.0/projects/PLAT/repos?limit=100&start=2": map[string]interface{}{
+			"values":     []interface{}{map[string]interface{}{"id": 13, "slug": "empty", "project": project}},
+			"isLastPage": true,
+		},
+		"/rest/api/1.0/users/jane/repos?limit=100&start=0": map[string]interface{}{"values": []interface{}{}, "isLastPage": true},
+	})
+	c, err := NewClient("token", "", srv.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	p, err := c.GetUserOrganization(ctx, "PLAT")
+	require.NoError(t, err)
+	assert.Equal(t, "PLAT", *p.Login)
+	assert.Equal(t, int64(7), *p.ID)
+	assert.Equal(t, _coreapi.TargetTypeOrganization, *p.Kind)
+
+	u, err := c.GetUserOrganization(ctx, "jane")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", *u.Login)
+	assert.Equal(t, _coreapi.TargetTypeUser, *u.Kind)
+
+	_, err = c.GetUserOrganization(ctx, "nobody")
+	assert.EqualError(t, err, "no Bitbucket project or user nobody was found. Projects are looked up by their key")
+
+	members, err := c.GetOrganizationMembers(ctx, *p)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "jane", *members[0].Login)
+
+	repos, err := c.GetRepositoriesFromOwner(ctx, *p)
+	require.NoError(t, err)
+	assert.Equal(t, []*_coreapi.Repository{
+		{Owner: "PLAT", ID: 11, Name: "api", FullName: "PLAT/api", CloneURL: "https://bb.example.com/scm/plat/api.git", SSHURL: "ssh://git@bb.example.com:7999/plat/api.git", URL: "https://bb.example.com/projects/PLAT/repos/api/browse", Homepage: "https://bb.example.com/projects/PLAT/repos/api/browse"},
+		{Owner: "PLAT", ID: 13, Name: "empty", FullName: "PLAT/empty"},
+	}, repos)
+
+	repos, err = c.GetRepositoriesFromOwner(ctx, *u)
+	require.NoError(t, err)
+	assert.Empty(t, repos)
+}
+
+// rewriteNext replaces the placeholder in the responses with the absolute URL of the next page
+func rewriteNext(h http.Handler, placeholder, next string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		body := rec.Body.String()
+		w.WriteHeader(rec.Code)
+		w.Write([]byte(strings.ReplaceAll(body, `"`+placeholder+`"`, `"`+next+`"`))) //nolint:errcheck
+	})
+}
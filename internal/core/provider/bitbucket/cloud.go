@@ -0,0 +1,192 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// CloudClient holds a Bitbucket Cloud api client instance, where workspaces are the organizations
+type CloudClient struct {
+	apiClient *httpapi.Client
+	apiURL    string
+}
+
+type cloudLinks struct {
+	HTML   cloudLink   `json:"html"`
+	Avatar cloudLink   `json:"avatar"`
+	Clone  []cloudLink `json:"clone"`
+}
+
+type cloudLink struct {
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
+
+type cloudWorkspace struct {
+	UUID  string     `json:"uuid"`
+	Slug  string     `json:"slug"`
+	Name  string     `json:"name"`
+	Links cloudLinks `json:"links"`
+}
+
+type cloudUser struct {
+	UUID        string     `json:"uuid"`
+	Nickname    string     `json:"nickname"`
+	DisplayName string     `json:"display_name"`
+	Links       cloudLinks `json:"links"`
+}
+
+type cloudRepository struct {
+	UUID        string     `json:"uuid"`
+	Slug        string     `json:"slug"`
+	FullName    string     `json:"full_name"`
+	Description string     `json:"description"`
+	Website     string     `json:"website"`
+	Links       cloudLinks `json:"links"`
+	Mainbranch  *struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Parent    *struct{}      `json:"parent"` // set for forks
+	Workspace cloudWorkspace `json:"workspace"`
+}
+
+type cloudMembership struct {
+	User cloudUser `json:"user"`
+}
+
+// GetUserOrganization is used to enumerate the owner in a given workspace, or the user with the given UUID
+// or account ID
+func (c *CloudClient) GetUserOrganization(ctx context.Context, login string) (*_coreapi.Owner, error) {
+	var ws cloudWorkspace
+	_, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/workspaces/%s", c.apiURL, url.PathEscape(login)), &ws)
+	if err == nil {
+		return &_coreapi.Owner{
+			Login:     util.StringToPointer(ws.Slug),
+			ID:        httpapi.HashID(ws.UUID),
+			Kind:      util.StringToPointer(_coreapi.TargetTypeOrganization),
+			Type:      util.StringToPointer(_coreapi.TargetTypeOrganization),
+			Name:      util.StringToPointer(ws.Name),
+			AvatarURL: util.StringToPointer(ws.Links.Avatar.Href),
+			URL:       util.StringToPointer(ws.Links.HTML.Href),
+		}, nil
+	}
+	if !errors.Is(err, httpapi.ErrNotFound) {
+		return nil, err
+	}
+	var user cloudUser
+	if _, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/users/%s", c.apiURL, url.PathEscape(login)), &user); err != nil {
+		if errors.Is(err, httpapi.ErrNotFound) {
+			return nil, fmt.Errorf("no Bitbucket workspace or user %s was found. Users are looked up by their UUID or account ID", login)
+		}
+		return nil, err
+	}
+	return cloudUserOwner(user), nil
+}
+
+// GetOrganizationMembers will gather all the members of a given workspace
+func (c *CloudClient) GetOrganizationMembers(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Owner, error) {
+	var members []*_coreapi.Owner
+	next := fmt.Sprintf("%s/workspaces/%s/members?pagelen=100", c.apiURL, url.PathEscape(*target.Login))
+	for next != "" {
+		var page struct {
+			Values []cloudMembership `json:"values"`
+			Next   string            `json:"next"`
+		}
+		if _, err := c.apiClient.Get(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		for _, m := range page.Values {
+			members = append(members, cloudUserOwner(m.User))
+		}
+		next = page.Next
+	}
+	return members, nil
+}
+
+// GetRepositoriesFromOwner is used gather all the repos of the workspace, or of the personal workspace of
+// the user
+func (c *CloudClient) GetRepositoriesFromOwner(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Repository, error) {
+	if target.Login == nil {
+		return nil, errors.New("Login field is not present")
+	}
+	var repos []*_coreapi.Repository
+	next := fmt.Sprintf("%s/repositories/%s?pagelen=100", c.apiURL, url.PathEscape(*target.Login))
+	for next != "" {
+		var page struct {
+			Values []cloudRepository `json:"values"`
+			Next   string            `json:"next"`
+		}
+		if _, err := c.apiClient.Get(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Values {
+			//don't capture forks
+			if r.Parent != nil {
+				continue
+			}
+			repo := &_coreapi.Repository{
+				Owner:       r.Workspace.Slug,
+				ID:          *httpapi.HashID(r.UUID),
+				Name:        r.Slug,
+				FullName:    r.FullName,
+				CloneURL:    cloneURL(r.Links.Clone, "https"),
+				SSHURL:      sshURL(r.Links.Clone),
+				URL:         r.Links.HTML.Href,
+				Description: r.Description,
+				Homepage:    r.Website,
+			}
+			if r.Mainbranch != nil {
+				repo.DefaultBranch = r.Mainbranch.Name
+			}
+			repos = append(repos, repo)
+		}
+		next = page.Next
+	}
+	return repos, nil
+}
+
+// userOwner returns the owner of the user, whose login is the UUID that selects the personal workspace
+func cloudUserOwner(user cloudUser) *_coreapi.Owner {
+	return &_coreapi.Owner{
+		Login:     util.StringToPointer(user.UUID),
+		ID:        httpapi.HashID(user.UUID),
+		Kind:      util.StringToPointer(_coreapi.TargetTypeUser),
+		Type:      util.StringToPointer(_coreapi.TargetTypeUser),
+		Name:      util.StringToPointer(user.DisplayName),
+		AvatarURL: util.StringToPointer(user.Links.Avatar.Href),
+		URL:       util.StringToPointer(user.Links.HTML.Href),
+	}
+}
+
+// cloneURL returns the clone link with the given name, without the username Bitbucket adds to it, since
+// the credentials of the scan are used
+func cloneURL(links []cloudLink, name string) string {
+	for _, l := range links {
+		if l.Name != name {
+			continue
+		}
+		u, err := url.Parse(l.Href)
+		if err != nil {
+			return l.Href
+		}
+		u.User = nil
+		return u.String()
+	}
+	return ""
+}
+
+// sshURL returns the ssh clone address, which keeps its user
+func sshURL(links []cloudLink) string {
+	for _, l := range links {
+		if l.Name == "ssh" {
+			return l.Href
+		}
+	}
+	return ""
+}
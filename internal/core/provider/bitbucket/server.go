@@ -0,0 +1,177 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// ServerClient holds a Bitbucket Server or Data Center api client instance, where projects are the
+// organizations
+type ServerClient struct {
+	apiClient *httpapi.Client
+	apiURL    string
+}
+
+type serverLinks struct {
+	Self  []cloudLink `json:"self"`
+	Clone []cloudLink `json:"clone"`
+}
+
+type serverProject struct {
+	ID          int64       `json:"id"`
+	Key         string      `json:"key"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Links       serverLinks `json:"links"`
+}
+
+type serverUser struct {
+	ID           int64       `json:"id"`
+	Slug         string      `json:"slug"`
+	DisplayName  string      `json:"displayName"`
+	EmailAddress string      `json:"emailAddress"`
+	Links        serverLinks `json:"links"`
+}
+
+type serverRepository struct {
+	ID          int64         `json:"id"`
+	Slug        string        `json:"slug"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Project     serverProject `json:"project"`
+	Links       serverLinks   `json:"links"`
+	Origin      *struct{}     `json:"origin"` // set for forks
+}
+
+type serverPage struct {
+	Values        json.RawMessage `json:"values"`
+	IsLastPage    bool            `json:"isLastPage"`
+	NextPageStart int             `json:"nextPageStart"`
+}
+
+// GetUserOrganization is used to enumerate the owner in a given project, by its key, or the user
+func (c *ServerClient) GetUserOrganization(ctx context.Context, login string) (*_coreapi.Owner, error) {
+	var p serverProject
+	_, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/projects/%s", c.apiURL, url.PathEscape(login)), &p)
+	if err == nil {
+		return &_coreapi.Owner{
+			Login: util.StringToPointer(p.Key),
+			ID:    &p.ID,
+			Kind:  util.StringToPointer(_coreapi.TargetTypeOrganization),
+			Type:  util.StringToPointer(_coreapi.TargetTypeOrganization),
+			Name:  util.StringToPointer(p.Name),
+			URL:   util.StringToPointer(selfLink(p.Links)),
+			Bio:   util.StringToPointer(p.Description),
+		}, nil
+	}
+	if !errors.Is(err, httpapi.ErrNotFound) {
+		return nil, err
+	}
+	var user serverUser
+	if _, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/users/%s", c.apiURL, url.PathEscape(login)), &user); err != nil {
+		if errors.Is(err, httpapi.ErrNotFound) {
+			return nil, fmt.Errorf("no Bitbucket project or user %s was found. Projects are looked up by their key", login)
+		}
+		return nil, err
+	}
+	return serverUserOwner(user), nil
+}
+
+// GetOrganizationMembers will gather the users with access to a given project
+func (c *ServerClient) GetOrganizationMembers(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Owner, error) {
+	var members []*_coreapi.Owner
+	err := c.list(ctx, fmt.Sprintf("%s/projects/%s/permissions/users", c.apiURL, url.PathEscape(*target.Login)), func(values json.RawMessage) error {
+		var permissions []struct {
+			User serverUser `json:"user"`
+		}
+		if err := json.Unmarshal(values, &permissions); err != nil {
+			return err
+		}
+		for _, p := range permissions {
+			members = append(members, serverUserOwner(p.User))
+		}
+		return nil
+	})
+	return members, err
+}
+
+// GetRepositoriesFromOwner is used gather all the repos of the project, or of the personal project of the user
+func (c *ServerClient) GetRepositoriesFromOwner(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Repository, error) {
+	if target.Login == nil || target.Type == nil {
+		return nil, errors.New("Login or Type fields are not present")
+	}
+	reposURL := fmt.Sprintf("%s/projects/%s/repos", c.apiURL, url.PathEscape(*target.Login))
+	if *target.Type == _coreapi.TargetTypeUser {
+		reposURL = fmt.Sprintf("%s/users/%s/repos", c.apiURL, url.PathEscape(*target.Login))
+	}
+	var repos []*_coreapi.Repository
+	err := c.list(ctx, reposURL, func(values json.RawMessage) error {
+		var page []serverRepository
+		if err := json.Unmarshal(values, &page); err != nil {
+			return err
+		}
+		for _, r := range page {
+			//don't capture forks
+			if r.Origin != nil {
+				continue
+			}
+			repos = append(repos, &_coreapi.Repository{
+				Owner:       r.Project.Key,
+				ID:          r.ID,
+				Name:        r.Slug,
+				FullName:    fmt.Sprintf("%s/%s", r.Project.Key, r.Slug),
+				CloneURL:    cloneURL(r.Links.Clone, "http"),
+				SSHURL:      sshURL(r.Links.Clone),
+				URL:         selfLink(r.Links),
+				Description: r.Description,
+				Homepage:    selfLink(r.Links),
+			})
+		}
+		return nil
+	})
+	return repos, err
+}
+
+// list will call fn with the values of every page of the paged resource
+func (c *ServerClient) list(ctx context.Context, resource string, fn func(json.RawMessage) error) error {
+	start := 0
+	for {
+		var page serverPage
+		if _, err := c.apiClient.Get(ctx, fmt.Sprintf("%s?limit=100&start=%d", resource, start), &page); err != nil {
+			return err
+		}
+		if err := fn(page.Values); err != nil {
+			return err
+		}
+		if page.IsLastPage || page.NextPageStart <= start {
+			return nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+func serverUserOwner(user serverUser) *_coreapi.Owner {
+	return &_coreapi.Owner{
+		Login: util.StringToPointer(user.Slug),
+		ID:    &user.ID,
+		Kind:  util.StringToPointer(_coreapi.TargetTypeUser),
+		Type:  util.StringToPointer(_coreapi.TargetTypeUser),
+		Name:  util.StringToPointer(user.DisplayName),
+		URL:   util.StringToPointer(selfLink(user.Links)),
+		Email: util.StringToPointer(user.EmailAddress),
+	}
+}
+
+func selfLink(links serverLinks) string {
+	if len(links.Self) == 0 {
+		return ""
+	}
+	return links.Self[0].Href
+}
@@ -5,6 +5,9 @@ import (
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/azure"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/bitbucket"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitea"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/github"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitlab"
 	scanAPI "github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
@@ -20,10 +23,14 @@ func InitGitClient(cfg *config.Config) (api.IClient, error) {
 			return nil, fmt.Errorf("github enterprise URL is missing")
 		}
 		return github.NewClient(cfg.Github.APIToken, cfg.Github.GithubEnterpriseURL)
+	case scanAPI.AzureDevOps:
+		return azure.NewClient(cfg.AzureDevOps.APIToken, cfg.AzureDevOps.URL)
+	case scanAPI.Bitbucket:
+		return bitbucket.NewClient(cfg.Bitbucket.APIToken, cfg.Bitbucket.Username, cfg.Bitbucket.URL)
+	case scanAPI.Gitea:
+		return gitea.NewClient(cfg.Gitea.APIToken, cfg.Gitea.URL)
 	case scanAPI.Gitlab:
-		// TODO need to add in the bits to parse the url here as well
-		// TODO set this to some sort of consistent client, look to github for ideas
-		return gitlab.NewClient(cfg.Gitlab.APIToken)
+		return gitlab.NewClient(cfg.Gitlab.APIToken, cfg.Gitlab.URL)
 	case scanAPI.UpdateSignatures:
 		return github.NewClient(cfg.Signatures.APIToken, "")
 	default:
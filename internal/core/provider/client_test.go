@@ -7,6 +7,9 @@ import (
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/azure"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/bitbucket"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitea"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/github"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitlab"
 	pkgapi "github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
@@ -27,7 +30,15 @@ func TestInitGitClient(t *testing.T) {
 		{"github enterprise, error, no token", config.Config{Global: config.Global{ScanType: pkgapi.GithubEnterprise}, Github: config.Github{GithubEnterpriseURL: "fake"}}, &github.Client{}, "cannot create new Github client, The token is invalid. Please use a valid Github token."},
 		{"github enterprise", config.Config{Global: config.Global{ScanType: pkgapi.GithubEnterprise}, Github: config.Github{GithubEnterpriseURL: "fake", APIToken: alphabet[:40]}}, &github.Client{}, ""},
 		{"gitlab", config.Config{Global: config.Global{ScanType: pkgapi.Gitlab}, Gitlab: config.Gitlab{APIToken: alphabet[:20]}}, &gitlab.Client{}, ""},
+		{"gitlab self-hosted", config.Config{Global: config.Global{ScanType: pkgapi.Gitlab}, Gitlab: config.Gitlab{APIToken: alphabet[:20], URL: "https://gitlab.example.com"}}, &gitlab.Client{}, ""},
 		{"gitlab, error, no token", config.Config{Global: config.Global{ScanType: pkgapi.Gitlab}}, &gitlab.Client{}, "Gitlab token is invalid"},
+		{"azure devops", config.Config{Global: config.Global{ScanType: pkgapi.AzureDevOps}, AzureDevOps: config.AzureDevOps{APIToken: "token"}}, &azure.Client{}, ""},
+		{"azure devops, error, no token", config.Config{Global: config.Global{ScanType: pkgapi.AzureDevOps}}, &azure.Client{}, "Azure DevOps token is not set"},
+		{"bitbucket cloud", config.Config{Global: config.Global{ScanType: pkgapi.Bitbucket}, Bitbucket: config.Bitbucket{APIToken: "token"}}, &bitbucket.CloudClient{}, ""},
+		{"bitbucket server", config.Config{Global: config.Global{ScanType: pkgapi.Bitbucket}, Bitbucket: config.Bitbucket{APIToken: "token", URL: "https://bitbucket.example.com"}}, &bitbucket.ServerClient{}, ""},
+		{"bitbucket, error, no token", config.Config{Global: config.Global{ScanType: pkgapi.Bitbucket}}, &bitbucket.CloudClient{}, "Bitbucket token is not set"},
+		{"gitea", config.Config{Global: config.Global{ScanType: pkgapi.Gitea}, Gitea: config.Gitea{APIToken: "token", URL: "https://gitea.example.com"}}, &gitea.Client{}, ""},
+		{"gitea, error, no token", config.Config{Global: config.Global{ScanType: pkgapi.Gitea}, Gitea: config.Gitea{URL: "https://gitea.example.com"}}, &gitea.Client{}, "Gitea token is not set"},
 		{"UpdateSignatures", config.Config{Global: config.Global{ScanType: pkgapi.UpdateSignatures}, Signatures: config.Signatures{APIToken: alphabet[:40]}}, &github.Client{}, ""},
 		{"UpdateSignatures, error, no token", config.Config{Global: config.Global{ScanType: pkgapi.UpdateSignatures}}, &github.Client{}, "cannot create new Github client, The token is invalid. Please use a valid Github token."},
 		{"error, unknown scan type", config.Config{}, &github.Client{}, "unknown scan type provided"},
@@ -0,0 +1,219 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/httpapi"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// pageSize is the default maximum of the instances, larger pages are cut to it
+const pageSize = 50
+
+// ClonePassword is the password for cloning with the token as the username
+const ClonePassword = "x-oauth-basic"
+
+// Client holds a Gitea, or Forgejo, api client instance
+type Client struct {
+	apiClient *httpapi.Client
+	apiURL    string
+}
+
+type user struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	Website   string `json:"website"`
+	Location  string `json:"location"`
+	Bio       string `json:"description"`
+}
+
+type organization struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"username"`
+	FullName    string `json:"full_name"`
+	AvatarURL   string `json:"avatar_url"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Location    string `json:"location"`
+}
+
+type repository struct {
+	ID            int64  `json:"id"`
+	Owner         user   `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Fork          bool   `json:"fork"`
+	Empty         bool   `json:"empty"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	HTMLURL       string `json:"html_url"`
+	DefaultBranch string `json:"default_branch"`
+	Website       string `json:"website"`
+}
+
+// NewClient creates a Gitea API client instance for the instance at the base URL
+func NewClient(token, baseURL string) (*Client, error) {
+	if token == "" {
+		return nil, errors.New("Gitea token is not set")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("unable to parse --gitea-url: %q", baseURL)
+	}
+	auth := func(r *http.Request) {
+		r.Header.Set("Authorization", "token "+token)
+	}
+	return &Client{apiClient: httpapi.New(auth), apiURL: WebURL(baseURL) + "/api/v1"}, nil
+}
+
+// WebURL returns the address of the instance with the given base URL
+func WebURL(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api/v1")
+}
+
+// Links returns the web addresses of the repository, and of the file and the commit in it
+func Links(baseURL, owner, name, commit, path string) (repoURL, fileURL, commitURL string) {
+	repoURL = fmt.Sprintf("%s/%s/%s", WebURL(baseURL), owner, name)
+	return repoURL, fmt.Sprintf("%s/src/commit/%s/%s", repoURL, commit, path), fmt.Sprintf("%s/commit/%s", repoURL, commit)
+}
+
+// RawURL returns the address of the content of the file at the commit
+func RawURL(baseURL, owner, name, commit, path string) string {
+	return fmt.Sprintf("%s/%s/%s/raw/commit/%s/%s", WebURL(baseURL), owner, name, commit, path)
+}
+
+// GetUserOrganization is used to enumerate the owner in a given org, or the user
+func (c *Client) GetUserOrganization(ctx context.Context, login string) (*_coreapi.Owner, error) {
+	var org organization
+	_, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/orgs/%s", c.apiURL, url.PathEscape(login)), &org)
+	if err == nil {
+		return &_coreapi.Owner{
+			Login:     util.StringToPointer(org.Name),
+			ID:        &org.ID,
+			Kind:      util.StringToPointer(_coreapi.TargetTypeOrganization),
+			Type:      util.StringToPointer(_coreapi.TargetTypeOrganization),
+			Name:      util.StringToPointer(org.FullName),
+			AvatarURL: util.StringToPointer(org.AvatarURL),
+			URL:       util.StringToPointer(fmt.Sprintf("%s/%s", WebURL(c.apiURL), org.Name)),
+			Blog:      util.StringToPointer(org.Website),
+			Location:  util.StringToPointer(org.Location),
+			Bio:       util.StringToPointer(org.Description),
+		}, nil
+	}
+	if !errors.Is(err, httpapi.ErrNotFound) {
+		return nil, err
+	}
+	var u user
+	if _, err := c.apiClient.Get(ctx, fmt.Sprintf("%s/users/%s", c.apiURL, url.PathEscape(login)), &u); err != nil {
+		if errors.Is(err, httpapi.ErrNotFound) {
+			return nil, fmt.Errorf("no Gitea organization or user %s was found", login)
+		}
+		return nil, err
+	}
+	return c.userOwner(u), nil
+}
+
+// GetOrganizationMembers will gather all the members of a given organization, the token needs to belong
+// to a member to see the private ones
+func (c *Client) GetOrganizationMembers(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Owner, error) {
+	var members []*_coreapi.Owner
+	err := c.list(ctx, fmt.Sprintf("%s/orgs/%s/members", c.apiURL, url.PathEscape(*target.Login)), func(values json.RawMessage) (int, error) {
+		var users []user
+		if err := json.Unmarshal(values, &users); err != nil {
+			return 0, err
+		}
+		for _, u := range users {
+			members = append(members, c.userOwner(u))
+		}
+		return len(users), nil
+	})
+	return members, err
+}
+
+// GetRepositoriesFromOwner is used gather all the repos of the org or user
+func (c *Client) GetRepositoriesFromOwner(ctx context.Context, target _coreapi.Owner) ([]*_coreapi.Repository, error) {
+	if target.Login == nil || target.Type == nil {
+		return nil, errors.New("Login or Type fields are not present")
+	}
+	reposURL := fmt.Sprintf("%s/orgs/%s/repos", c.apiURL, url.PathEscape(*target.Login))
+	if *target.Type == _coreapi.TargetTypeUser {
+		reposURL = fmt.Sprintf("%s/users/%s/repos", c.apiURL, url.PathEscape(*target.Login))
+	}
+	var repos []*_coreapi.Repository
+	err := c.list(ctx, reposURL, func(values json.RawMessage) (int, error) {
+		var page []repository
+		if err := json.Unmarshal(values, &page); err != nil {
+			return 0, err
+		}
+		for _, r := range page {
+			//don't capture forks, empty repositories can't be cloned
+			if r.Fork || r.Empty {
+				continue
+			}
+			repos = append(repos, &_coreapi.Repository{
+				Owner:         r.Owner.Login,
+				ID:            r.ID,
+				Name:          r.Name,
+				FullName:      r.FullName,
+				CloneURL:      r.CloneURL,
+				SSHURL:        r.SSHURL,
+				URL:           r.HTMLURL,
+				DefaultBranch: r.DefaultBranch,
+				Description:   r.Description,
+				Homepage:      r.Website,
+			})
+		}
+		return len(page), nil
+	})
+	return repos, err
+}
+
+// list will call fn with every page of the paged resource, until a page is empty or all the items of the
+// X-Total-Count header are read. The func returns the number of items in the page.
+func (c *Client) list(ctx context.Context, resource string, fn func(json.RawMessage) (int, error)) error {
+	read := 0
+	for page := 1; ; page++ {
+		var values json.RawMessage
+		h, err := c.apiClient.Get(ctx, fmt.Sprintf("%s?limit=%d&page=%d", resource, pageSize, page), &values)
+		if err != nil {
+			return err
+		}
+		n, err := fn(values)
+		if err != nil {
+			return err
+		}
+		read += n
+		total, err := strconv.Atoi(h.Get("X-Total-Count"))
+		if n == 0 || (err == nil && read >= total) {
+			return nil
+		}
+	}
+}
+
+func (c *Client) userOwner(u user) *_coreapi.Owner {
+	return &_coreapi.Owner{
+		Login:     util.StringToPointer(u.Login),
+		ID:        &u.ID,
+		Kind:      util.StringToPointer(_coreapi.TargetTypeUser),
+		Type:      util.StringToPointer(_coreapi.TargetTypeUser),
+		Name:      util.StringToPointer(u.FullName),
+		AvatarURL: util.StringToPointer(u.AvatarURL),
+		URL:       util.StringToPointer(fmt.Sprintf("%s/%s", WebURL(c.apiURL), u.Login)),
+		Blog:      util.StringToPointer(u.Website),
+		Location:  util.StringToPointer(u.Location),
+		Email:     util.StringToPointer(u.Email),
+		Bio:       util.StringToPointer(u.Bio),
+	}
+}
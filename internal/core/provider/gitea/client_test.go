@@ -0,0 +1,112 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stub serves the JSON responses by path and query with the total count of the paged ones, and 404 for
+// anything else
+func stub(t *testing.T, responses map[string]interface{}, totals map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		key := r.URL.Path
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		res, ok := responses[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if total, ok := totals[r.URL.Path]; ok {
+			w.Header().Set("X-Total-Count", total)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("secret", "https://gitea.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitea.example.com/api/v1", c.apiURL)
+
+	_, err = NewClient("", "https://gitea.example.com")
+	assert.EqualError(t, err, "Gitea token is not set")
+	_, err = NewClient("secret", "gitea.example.com")
+	assert.EqualError(t, err, `unable to parse --gitea-url: "gitea.example.com"`)
+}
+
+func TestLinks(t *testing.T) {
+	repo, file, commit := Links("https://gitea.example.com/", "infra", "api", "abc", "config/app.yaml")
+	assert.Equal(t, "https://gitea.example.com/infra/api", repo)
+	assert.Equal(t, "https://gitea.example.com/infra/api/src/commit/abc/config/app.yaml", file)
+	assert.Equal(t, "https://gitea.example.com/infra/api/commit/abc", commit)
+	assert.Equal(t, "https://gitea.example.com/infra/api/raw/commit/abc/config/app.yaml", RawURL("https://gitea.example.com", "infra", "api", "abc", "config/app.yaml"))
+}
+
+func TestClient(t *testing.T) {
+	jane := map[string]interface{}{"id": 3, "login": "jane", "full_name": "Jane"}
+	repo := func(id int, name string, extra map[string]interface{}) map[string]interface{} {
+		r := map[string]interface{}{"id": id, "name": name, "full_name": "infra/" + name, "owner": map[string]string{"login": "infra"},
+			"clone_url": "https://gitea.example.com/infra/" + name + ".git", "ssh_url": "git@gitea.example.com:infra/" + name + ".git", "html_url": "https://gitea.example.com/infra/" + name, "default_branch": "main"}
+		for k, v := range extra {
+			r[k] = v
+		}
+		return r
+	}
+	srv := stub(t, map[string]interface{}{
+		"/api/v1/orgs/infra":                         map[string]interface{}{"id": 7, "username": "infra", "full_name": "Infrastructure"},
+		"/api/v1/users/jane":                         jane,
+		"/api/v1/orgs/infra/members?limit=50&page=1": []interface{}{jane},
+		"/api/v1/orgs/infra/members?limit=50&page=2": []interface{}{},
+		"/api/v1/orgs/infra/repos?limit=50&page=1":   []interface{}{repo(11, "api", nil), repo(12, "api-fork", map[string]interface{}{"fork": true})},
+		"/api/v1/orgs/infra/repos?limit=50&page=2":   []interface{}{repo(13, "empty", map[string]interface{}{"empty": true}), repo(14, "web", nil)},
+		"/api/v1/users/jane/repos?limit=50&page=1":   []interface{}{},
+		"/api/v1/orgs/infra/repos?limit=50&page=3":   []interface{}{repo(15, "never-read", nil)},
+	}, map[string]string{"/api/v1/orgs/infra/repos": "4"})
+	c, err := NewClient("secret", srv.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	org, err := c.GetUserOrganization(ctx, "infra")
+	require.NoError(t, err)
+	assert.Equal(t, "infra", *org.Login)
+	assert.Equal(t, int64(7), *org.ID)
+	assert.Equal(t, _coreapi.TargetTypeOrganization, *org.Kind)
+	assert.Equal(t, srv.URL+"/infra", *org.URL)
+
+	user, err := c.GetUserOrganization(ctx, "jane")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", *user.Login)
+	assert.Equal(t, _coreapi.TargetTypeUser, *user.Kind)
+
+	_, err = c.GetUserOrganization(ctx, "nobody")
+	assert.EqualError(t, err, "no Gitea organization or user nobody was found")
+
+	members, err := c.GetOrganizationMembers(ctx, *org)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "Jane", *members[0].Name)
+
+	// pages are read until all the repositories of the total count are
+	repos, err := c.GetRepositoriesFromOwner(ctx, *org)
+	require.NoError(t, err)
+	assert.Equal(t, []*_coreapi.Repository{
+		{Owner: "infra", ID: 11, Name: "api", FullName: "infra/api", CloneURL: "https://gitea.example.com/infra/api.git", SSHURL: "git@gitea.example.com:infra/api.git", URL: "https://gitea.example.com/infra/api", DefaultBranch: "main"},
+		{Owner: "infra", ID: 14, Name: "web", FullName: "infra/web", CloneURL: "https://gitea.example.com/infra/web.git", SSHURL: "git@gitea.example.com:infra/web.git", URL: "https://gitea.example.com/infra/web", DefaultBranch: "main"},
+	}, repos)
+
+	repos, err = c.GetRepositoriesFromOwner(ctx, *user)
+	require.NoError(t, err)
+	assert.Empty(t, repos)
+}
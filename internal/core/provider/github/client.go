@@ -203,6 +203,7 @@ func githubToAPIRepos(repos []*github.Repository) []*_coreapi.Repository {
 				Name:          util.PointerToString(repo.Name),
 				FullName:      util.PointerToString(repo.FullName),
 				CloneURL:      util.PointerToString(repo.CloneURL),
+				SSHURL:        util.PointerToString(repo.SSHURL),
 				URL:           util.PointerToString(repo.HTMLURL),
 				DefaultBranch: util.PointerToString(repo.DefaultBranch),
 				Description:   util.PointerToString(repo.Description),
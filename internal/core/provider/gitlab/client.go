@@ -20,16 +20,21 @@ type Client struct {
 	apiClient *gitlab.Client
 }
 
-// NewClient creates a Gitlab API client instance
-func NewClient(token string) (*Client, error) {
+// NewClient creates a Gitlab API client instance, for gitlab.com unless the address of a self-hosted
+// instance is given
+func NewClient(token, baseURL string) (*Client, error) {
 	err := validateAPIToken(token)
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := gitlab.NewClient(token)
+	var opts []gitlab.ClientOptionFunc
+	if baseURL != "" {
+		opts = append(opts, gitlab.WithBaseURL(baseURL))
+	}
+	c, err := gitlab.NewClient(token, opts...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to parse --gitlab-url: %q, %w", baseURL, err)
 	}
 	c.UserAgent = version.UserAgent
 
@@ -39,6 +44,19 @@ func NewClient(token string) (*Client, error) {
 	return client, nil
 }
 
+// WebURL returns the address of the instance with the given base URL, gitlab.com when it's empty
+func WebURL(baseURL string) string {
+	if baseURL == "" {
+		return Address
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api/v4")
+}
+
+// RawURL returns the address of the content of the file at the commit
+func RawURL(baseURL, owner, name, commit, path string) string {
+	return fmt.Sprintf("%s/%s/%s/-/raw/%s/%s", WebURL(baseURL), owner, name, commit, path)
+}
+
 // validateAPIToken will ensure we have a valid github api token
 func validateAPIToken(t string) error {
 	// check to make sure the length is proper
@@ -61,6 +79,7 @@ func (c *Client) GetUserOrganization(ctx context.Context, login string) (*_corea
 		return &_coreapi.Owner{
 			Login:     gitlab.String(user.Username),
 			ID:        &id,
+			Kind:      gitlab.String(_coreapi.TargetTypeUser),
 			Type:      gitlab.String(_coreapi.TargetTypeUser),
 			Name:      gitlab.String(user.Name),
 			AvatarURL: gitlab.String(user.AvatarURL),
@@ -76,6 +95,7 @@ func (c *Client) GetUserOrganization(ctx context.Context, login string) (*_corea
 	return &_coreapi.Owner{
 		Login:     gitlab.String(org.Name),
 		ID:        &id,
+		Kind:      gitlab.String(_coreapi.TargetTypeOrganization),
 		Type:      gitlab.String(_coreapi.TargetTypeOrganization),
 		Name:      gitlab.String(org.Name),
 		AvatarURL: gitlab.String(org.AvatarURL),
@@ -105,6 +125,7 @@ func (c *Client) GetOrganizationMembers(ctx context.Context, target _coreapi.Own
 				&_coreapi.Owner{
 					Login: gitlab.String(member.Username),
 					ID:    &id,
+					Kind:  gitlab.String(_coreapi.TargetTypeUser),
 					Type:  gitlab.String(_coreapi.TargetTypeUser)})
 		}
 		if resp.NextPage == 0 {
@@ -193,6 +214,7 @@ func (c *Client) getUserProjects(id int) ([]*_coreapi.Repository, error) {
 						Name:          project.Name,
 						FullName:      project.NameWithNamespace,
 						CloneURL:      project.HTTPURLToRepo,
+						SSHURL:        project.SSHURLToRepo,
 						URL:           project.WebURL,
 						DefaultBranch: project.DefaultBranch,
 						Description:   project.Description,
@@ -244,6 +266,7 @@ func (c *Client) getGroupProjects(target _coreapi.Owner) ([]*_coreapi.Repository
 						Name:          project.Name,
 						FullName:      project.NameWithNamespace,
 						CloneURL:      project.HTTPURLToRepo,
+						SSHURL:        project.SSHURLToRepo,
 						URL:           project.WebURL,
 						DefaultBranch: project.DefaultBranch,
 						Description:   project.Description,
@@ -13,26 +13,45 @@ import (
 func Test_NewClient(t *testing.T) {
 	var token = "abcdefghijklmnopqrstuvwxyz"
 	t.Run("ok", func(t *testing.T) {
-		client, err := NewClient(token[:20])
+		client, err := NewClient(token[:20], "")
 		assert.NoError(t, err)
 		assert.NotNil(t, client)
 	})
 
 	t.Run("less chars, error", func(t *testing.T) {
-		client, err := NewClient(token[:18])
+		client, err := NewClient(token[:18], "")
 		assert.Error(t, err)
 		assert.EqualError(t, err, "Gitlab token is invalid")
 		assert.Nil(t, client)
 	})
 
+	t.Run("self-hosted", func(t *testing.T) {
+		client, err := NewClient(token[:20], "https://gitlab.example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://gitlab.example.com/api/v4/", client.apiClient.BaseURL().String())
+	})
+
+	t.Run("self-hosted, error, invalid url", func(t *testing.T) {
+		client, err := NewClient(token[:20], "://gitlab")
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
 	t.Run("more chars, error", func(t *testing.T) {
-		client, err := NewClient(token)
+		client, err := NewClient(token, "")
 		assert.Error(t, err)
 		assert.EqualError(t, err, "Gitlab token is invalid")
 		assert.Nil(t, client)
 	})
 }
 
+func TestWebURL(t *testing.T) {
+	assert.Equal(t, "https://gitlab.com", WebURL(""))
+	assert.Equal(t, "https://gitlab.example.com", WebURL("https://gitlab.example.com/"))
+	assert.Equal(t, "https://example.com/gitlab", WebURL("https://example.com/gitlab/api/v4"))
+	assert.Equal(t, "https://gitlab.example.com/infra/api/-/raw/abc/config/app.yaml", RawURL("https://gitlab.example.com/api/v4", "infra", "api", "abc", "config/app.yaml"))
+}
+
 func Test_GetUserOrganization(t *testing.T) {
 	glClient, err := gitlab.NewClient("", gitlab.WithBaseURL(mockedURL))
 	assert.NoError(t, err)
@@ -47,6 +66,7 @@ func Test_GetUserOrganization(t *testing.T) {
 		assert.Equal(t, "john", *owner.Name)
 		assert.Equal(t, int64(5), *owner.ID)
 		assert.Equal(t, "User", *owner.Type)
+		assert.Equal(t, "User", *owner.Kind)
 	})
 
 	t.Run("Get User 2, error", func(t *testing.T) {
@@ -65,6 +85,7 @@ func Test_GetUserOrganization(t *testing.T) {
 		assert.Equal(t, "foobar123thisorgwasmocked", *owner.Name)
 		assert.Equal(t, int64(1), *owner.ID)
 		assert.Equal(t, "Organization", *owner.Type)
+		assert.Equal(t, "Organization", *owner.Kind)
 	})
 }
 
@@ -0,0 +1,83 @@
+// Package httpapi is a small JSON client for the REST APIs of the git servers without a Go SDK
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rumenvasilev/rvsecret/version"
+)
+
+// ErrNotFound is returned for 404 responses, e.g. when looking up a user that is an organization
+var ErrNotFound = errors.New("not found")
+
+// HashID returns a numeric ID for the string IDs of the APIs, as the GUIDs of Azure DevOps and the UUIDs
+// of Bitbucket Cloud
+func HashID(id string) *int64 {
+	h := fnv.New64a()
+	h.Write([]byte(id)) //nolint:errcheck
+	n := int64(h.Sum64() >> 1)
+	return &n
+}
+
+// Client sends authenticated requests to an API
+type Client struct {
+	HTTP *http.Client
+	Auth func(*http.Request) // sets the credentials of the request
+}
+
+// New returns a client that authenticates with the auth func
+func New(auth func(*http.Request)) *Client {
+	return &Client{HTTP: &http.Client{Timeout: 30 * time.Second}, Auth: auth}
+}
+
+// BearerAuth authenticates with the token in the Authorization header
+func BearerAuth(token string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// BasicAuth authenticates with the username and password, which is often a token
+func BasicAuth(username, password string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.SetBasicAuth(username, password)
+	}
+}
+
+// Get will decode the JSON response of the URL into v, and return the headers of the response, which
+// some APIs use for pagination
+func (c *Client) Get(ctx context.Context, url string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+	if c.Auth != nil {
+		c.Auth(req)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return resp.Header, fmt.Errorf("GET %s, %w", url, ErrNotFound)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.Header, fmt.Errorf("GET %s, unexpected status %s: %s", url, resp.Status, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp.Header, fmt.Errorf("GET %s, invalid response, %w", url, err)
+	}
+	return resp.Header, nil
+}
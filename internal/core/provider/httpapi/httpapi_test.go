@@ -0,0 +1,53 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Get(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		switch {
+		case user != "jane" || pass != "secret":
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+		case r.URL.Path == "/ok":
+			w.Header().Set("Link", "next")
+			w.Write([]byte(`{"name": "api"}`)) //nolint:errcheck
+		case r.URL.Path == "/invalid":
+			w.Write([]byte(`{`)) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	c := New(BasicAuth("jane", "secret"))
+
+	var v struct{ Name string }
+	h, err := c.Get(ctx, srv.URL+"/ok", &v)
+	require.NoError(t, err)
+	assert.Equal(t, "api", v.Name)
+	assert.Equal(t, "next", h.Get("Link"))
+
+	_, err = c.Get(ctx, srv.URL+"/missing", &v)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = c.Get(ctx, srv.URL+"/invalid", &v)
+	assert.ErrorContains(t, err, "invalid response")
+
+	_, err = New(BearerAuth("token")).Get(ctx, srv.URL+"/ok", &v)
+	assert.ErrorContains(t, err, "401 Unauthorized: bad credentials")
+}
+
+func TestHashID(t *testing.T) {
+	a := HashID("{0b4f7c1e-2f3a-4c55-9d2e-6a1b7c8d9e0f}")
+	assert.Equal(t, a, HashID("{0b4f7c1e-2f3a-4c55-9d2e-6a1b7c8d9e0f}"))
+	assert.NotEqual(t, a, HashID("{5d2c8e7a-1b3f-4a6d-8c9e-0f1a2b3c4d5e}"))
+	assert.GreaterOrEqual(t, *a, int64(0))
+}
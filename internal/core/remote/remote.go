@@ -0,0 +1,153 @@
+// Package remote turns the clone URLs of git repositories on hosts without a supported API into
+// repositories to scan
+package remote
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	_coreapi "github.com/rumenvasilev/rvsecret/internal/core/api"
+	"gopkg.in/src-d/go-git.v4"
+	gitconfig "gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// Repository returns the repository to clone from the URL, which is an https, ssh or scp-like address as
+// in git@example.com:team/app.git. The host is the owner, and the default branch is resolved when cloning.
+// The user and password of http URLs are left out of the repository, Credential returns them.
+func Repository(rawURL string) (*_coreapi.Repository, error) {
+	ep, err := transport.NewEndpoint(rawURL)
+	if err != nil {
+		// the error of the parser repeats the URL
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid remote %s, %w", redact(rawURL), err)
+	}
+	repoPath := strings.TrimSuffix(strings.Trim(ep.Path, "/"), ".git")
+	if repoPath == "" {
+		return nil, fmt.Errorf("invalid remote %s, the repository path is missing", redact(rawURL))
+	}
+	// ssh needs the user, which isn't a secret
+	cloneURL := rawURL
+	stripped := *ep
+	stripped.Password = ""
+	if isHTTP(ep) {
+		stripped.User = ""
+	}
+	if stripped != *ep {
+		cloneURL = stripped.String()
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(cloneURL)) //nolint:errcheck
+	return &_coreapi.Repository{
+		Owner:    ep.Host,
+		ID:       int64(h.Sum64() >> 1),
+		Name:     path.Base(repoPath),
+		FullName: strings.TrimPrefix(ep.Host+"/"+repoPath, "/"),
+		CloneURL: cloneURL,
+		URL:      stripped.String(),
+	}, nil
+}
+
+// Credential returns the user and password in the http URL, or nil when there are none and for the other
+// protocols
+func Credential(rawURL string) *config.Credential {
+	ep, err := transport.NewEndpoint(rawURL)
+	if err != nil || !isHTTP(ep) || (ep.User == "" && ep.Password == "") {
+		return nil
+	}
+	return &config.Credential{Host: ep.Host, Username: ep.User, Password: ep.Password}
+}
+
+// ReadFile returns the clone URLs in the file, one per line. Empty lines and comments starting with a #
+// are skipped.
+func ReadFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return read(f)
+}
+
+func read(r io.Reader) ([]string, error) {
+	var urls []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, s.Err()
+}
+
+// BasicAuth returns the credential of the host of the http URL, then the one taken out of the URL, or nil
+// when there is none and for the other protocols
+func BasicAuth(remotes config.Remotes, rawURL string) transport.AuthMethod {
+	ep, err := transport.NewEndpoint(rawURL)
+	if err != nil || !isHTTP(ep) {
+		return nil
+	}
+	hostPort := fmt.Sprintf("%s:%d", ep.Host, ep.Port)
+	for _, c := range remotes.Credentials {
+		if strings.EqualFold(c.Host, ep.Host) || strings.EqualFold(c.Host, hostPort) {
+			return &http.BasicAuth{Username: c.Username, Password: c.Password}
+		}
+	}
+	if c, ok := remotes.URLCredentials[rawURL]; ok {
+		return &http.BasicAuth{Username: c.Username, Password: c.Password}
+	}
+	return nil
+}
+
+// redact hides the user and password of the URL, which is shown in the errors
+func redact(rawURL string) string {
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return rawURL
+	}
+	authority := rest
+	if i := strings.Index(rest, "/"); i >= 0 {
+		authority = rest[:i]
+	}
+	i := strings.LastIndex(authority, "@")
+	if i < 0 {
+		return rawURL
+	}
+	return scheme + "://xxxxx" + rest[i:]
+}
+
+func isHTTP(ep *transport.Endpoint) bool {
+	return ep.Protocol == "http" || ep.Protocol == "https"
+}
+
+// DefaultBranch returns the branch the HEAD of the remote points to. Cloning a single branch doesn't follow
+// the HEAD, it's taken to be master.
+func DefaultBranch(rawURL string, auth transport.AuthMethod) (string, error) {
+	refs, err := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: "origin", URLs: []string{rawURL}}).List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference && ref.Target().IsBranch() {
+			return ref.Target().Short(), nil
+		}
+	}
+	return "", errors.New("the HEAD of the remote doesn't point to a branch")
+}
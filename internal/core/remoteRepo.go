@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core/remote"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+	"github.com/rumenvasilev/rvsecret/internal/stats"
+)
+
+// GatherRemoteRepositories will add the clone URLs the user passed, and the ones in the file, as the
+// repositories to scan. There is no enumeration, every URL is a repository.
+func GatherRemoteRepositories(sess *session.Session) error {
+	log := log.Log
+	urls := sess.Config.Remotes.URLs
+	if sess.Config.Remotes.File != "" {
+		fromFile, err := remote.ReadFile(sess.Config.Remotes.File)
+		if err != nil {
+			return fmt.Errorf("cannot read the remotes file, %w", err)
+		}
+		urls = append(urls, fromFile...)
+	}
+	if len(urls) == 0 {
+		return errors.New("no remotes to scan, pass their clone URLs or a file with them")
+	}
+
+	// TODO - must use a method, to be memsafe like everything else
+	sess.State.Stats.Targets = len(urls)
+	sess.State.Stats.UpdateStatus(stats.StatusGathering)
+	log.Important("Gathering remote repositories...")
+
+	for _, u := range urls {
+		repo, err := remote.Repository(u)
+		if err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		if c := remote.Credential(u); c != nil {
+			if sess.Config.Remotes.URLCredentials == nil {
+				sess.Config.Remotes.URLCredentials = make(map[string]config.Credential)
+			}
+			sess.Config.Remotes.URLCredentials[repo.CloneURL] = *c
+		}
+		sess.State.AddRepository(repo)
+	}
+	return nil
+}
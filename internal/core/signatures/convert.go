@@ -0,0 +1,45 @@
+package signatures
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Convert will read a signatures file in any of the supported formats: rvsecret YAML, gitleaks TOML
+// or trufflehog custom detectors YAML. The warnings describe the rule options of other formats that
+// couldn't be expressed as signatures and were dropped.
+func Convert(filename string) (SignatureConfig, []LintIssue, error) {
+	if !util.PathExists(filename) {
+		return SignatureConfig{}, nil, errors.New("Couldn't find the path to signatures file")
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return SignatureConfig{}, nil, err
+	}
+
+	var c SignatureConfig
+	var warnings []LintIssue
+	switch {
+	case strings.EqualFold(filepath.Ext(filename), ".toml"):
+		c, warnings, err = parseGitleaks(data)
+	case isTrufflehog(data):
+		c, warnings, err = parseTrufflehog(data)
+	default:
+		err = yaml.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return SignatureConfig{}, nil, err
+	}
+
+	for _, defs := range [][]SignatureDef{c.SimpleSignatures, c.PatternSignatures, c.SafeFunctionSignatures} {
+		for i := range defs {
+			defs[i].Source = filename
+		}
+	}
+	return c, warnings, nil
+}
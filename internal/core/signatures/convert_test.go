@@ -0,0 +1,141 @@
+package signatures
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSecretGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		group   int
+		want    string
+		wantErr string
+	}{
+		{"no group", `AKIA[0-9A-Z]{16}`, 0, `AKIA[0-9A-Z]{16}`, ""},
+		{"first group", `key=(\w+)`, 1, `key=(?P<secret>\w+)`, ""},
+		{"skips non-capturing", `(?i)(?:key|token)=(\w+)`, 1, `(?i)(?:key|token)=(?P<secret>\w+)`, ""},
+		{"second group", `(user):(pass)`, 2, `(user):(?P<secret>pass)`, ""},
+		{"renames named group", `(?P<value>\w+)`, 1, `(?P<secret>\w+)`, ""},
+		{"escaped and class parens", `\(([()]+)\)(x)`, 2, `\(([()]+)\)(?P<secret>x)`, ""},
+		{"missing group", `(a)`, 2, "", "secretGroup 2 doesn't exist"},
+		{"invalid", `(a`, 1, "", "invalid regular expression, error parsing regexp: missing closing ): `(a`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withSecretGroup(tt.expr, tt.group)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NotPanics(t, func() { regexp.MustCompile(got) })
+		})
+	}
+}
+
+func TestConvert_gitleaks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "gitleaks.toml")
+	writeFile(t, file, `
+title = "gitleaks config"
+
+[[rules]]
+id = "generic-api-key"
+description = "Generic API Key"
+regex = '''(?i)(?:key|token)\s*=\s*['"]?([0-9a-z]{10,})['"]?'''
+entropy = 3.5
+keywords = ["key", "token"]
+tags = ["generic"]
+[rules.allowlist]
+stopwords = ["example"]
+paths = ['''test/''']
+
+[[rules]]
+id = "pkcs12-file"
+path = '''(?i)\.p12$'''
+
+[[rules]]
+id = "empty"
+`)
+
+	c, warnings, err := Convert(file)
+	require.NoError(t, err)
+	assert.Equal(t, []SignatureDef{
+		{
+			SignatureID: "generic-api-key", Description: "Generic API Key", Comment: "imported from gitleaks",
+			Match: `(?i)(?:key|token)\s*=\s*['"]?(?P<secret>[0-9a-z]{10,})['"]?`, Part: "partcontent",
+			Tags: []string{"generic"}, Stopwords: []string{"example"}, Entropy: 3.5,
+			Enable: 1, ConfidenceLevel: 3, Source: file,
+		},
+		{
+			SignatureID: "pkcs12-file", Description: "pkcs12-file", Comment: "imported from gitleaks",
+			Match: `(?i)\.p12$`, Part: "partpath", Enable: 1, ConfidenceLevel: 3, Source: file,
+		},
+	}, c.PatternSignatures)
+	assert.Equal(t, []LintIssue{
+		{"generic-api-key", LintWarning, "keywords are not supported, the regex is matched against every file"},
+		{"generic-api-key", LintWarning, "allowlist regexes, paths and commits are not supported, only stopwords are imported"},
+		{"empty", LintWarning, "rule has neither regex nor path, skipping it"},
+	}, warnings)
+	assert.Empty(t, Lint(c))
+}
+
+func TestConvert_trufflehog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "detectors.yaml")
+	writeFile(t, file, `
+detectors:
+  - name: Hog Token
+    keywords: [hog]
+    regex:
+      token: '\b(HOG[0-9A-Z]{17})\b'
+    exclude_words: [HOGEXAMPLE]
+    verify:
+      - endpoint: http://localhost:8000/
+  - name: Pig
+    regex:
+      id: 'PIG[0-9]{4}'
+      secret: 'pigsecret=([a-z0-9]{32})'
+`)
+
+	c, warnings, err := Convert(file)
+	require.NoError(t, err)
+	assert.Equal(t, []SignatureDef{
+		{
+			SignatureID: "hog-token", Description: "Hog Token", Comment: "imported from trufflehog",
+			Match: `\b(?P<secret>HOG[0-9A-Z]{17})\b`, Part: "partcontent", Stopwords: []string{"HOGEXAMPLE"},
+			Enable: 1, ConfidenceLevel: 3, Source: file,
+		},
+		{
+			SignatureID: "pig-id", Description: "Pig (id)", Comment: "imported from trufflehog",
+			Match: `PIG[0-9]{4}`, Part: "partcontent", Enable: 1, ConfidenceLevel: 3, Source: file,
+		},
+		{
+			SignatureID: "pig-secret", Description: "Pig (secret)", Comment: "imported from trufflehog",
+			Match: `pigsecret=(?P<secret>[a-z0-9]{32})`, Part: "partcontent", Enable: 1, ConfidenceLevel: 3, Source: file,
+		},
+	}, c.PatternSignatures)
+	assert.Equal(t, []LintIssue{
+		{"hog-token", LintWarning, "keywords are not supported, the regex is matched against every file"},
+		{"hog-token", LintWarning, "verification endpoints are not supported, ignoring them"},
+		{"pig", LintWarning, "detectors requiring all of their 2 regexes to match are not supported, every regex is imported as a separate signature"},
+	}, warnings)
+}
+
+func TestConvert_native(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "signatures.yaml")
+	writeFile(t, file, `
+PatternSignatures:
+  - signatureid: native
+    match: abc
+`)
+	c, warnings, err := Convert(file)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, []SignatureDef{{SignatureID: "native", Match: "abc", Source: file}}, c.PatternSignatures)
+}
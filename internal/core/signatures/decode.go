@@ -0,0 +1,174 @@
+package signatures
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Encodings of the blobs decoded before matching
+const (
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+	EncodingURL    = "url"
+)
+
+// maxDecodedSegments bounds the work done on a single file, e.g. a minified bundle full of base64 images
+const maxDecodedSegments = 10000
+
+var (
+	base64Blob = regexp.MustCompile(`[A-Za-z0-9+/_-]{12,}={0,2}`)
+	hexBlob    = regexp.MustCompile(`(?:\\x[0-9a-fA-F]{2}){4,}|\b(?:[0-9a-fA-F]{2}){8,}\b`)
+	urlBlob    = regexp.MustCompile(`[^\s"'<>%]*(?:%[0-9a-fA-F]{2}[^\s"'<>%]*)+`)
+)
+
+// decodedSegment is a line of content with an encoded blob replaced by its decoded text
+type decodedSegment struct {
+	text     string // the line, with the blob decoded
+	encoding string // the encoding of the blob, e.g. base64, or base64+url for nested encodings
+	encoded  string // the blob as found in the content
+	start    int    // offset of the decoded text within text
+	end      int
+	line     int
+}
+
+// decodedMatch is a match of a pattern involving decoded text
+type decodedMatch struct {
+	segment decodedSegment
+	patternMatch
+}
+
+// decodeContent will find the plausible encoded blobs within every line of the content and decode them,
+// recursively up to the given depth
+func decodeContent(content string, depth int) []decodedSegment {
+	var res []decodedSegment
+	if depth <= 0 {
+		return res
+	}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		res = appendDecoded(res, decodedSegment{text: line, end: len(line), line: i + 1}, depth)
+		if len(res) >= maxDecodedSegments {
+			break
+		}
+	}
+	return res
+}
+
+// appendDecoded will decode the blobs within the decoded text of the parent segment
+func appendDecoded(res []decodedSegment, parent decodedSegment, depth int) []decodedSegment {
+	haystack := parent.text[parent.start:parent.end]
+	for _, b := range findBlobs(haystack) {
+		if len(res) >= maxDecodedSegments {
+			return res
+		}
+		start, end := parent.start+b.start, parent.start+b.end
+		seg := decodedSegment{
+			text:     parent.text[:start] + b.decoded + parent.text[end:],
+			encoding: b.encoding,
+			encoded:  parent.encoded,
+			start:    start,
+			end:      start + len(b.decoded),
+			line:     parent.line,
+		}
+		if parent.encoding != "" {
+			seg.encoding = parent.encoding + "+" + b.encoding
+		}
+		if seg.encoded == "" {
+			seg.encoded = haystack[b.start:b.end]
+		}
+		res = append(res, seg)
+		if depth > 1 {
+			res = appendDecoded(res, seg, depth-1)
+		}
+	}
+	return res
+}
+
+type blob struct {
+	encoding string
+	decoded  string
+	start    int
+	end      int
+}
+
+// findBlobs returns the encoded blobs within the text that decode into readable text
+func findBlobs(text string) []blob {
+	var res []blob
+	for _, loc := range hexBlob.FindAllStringIndex(text, -1) {
+		if decoded, ok := decodeHex(text[loc[0]:loc[1]]); ok {
+			res = append(res, blob{EncodingHex, decoded, loc[0], loc[1]})
+		}
+	}
+	for _, loc := range base64Blob.FindAllStringIndex(text, -1) {
+		if decoded, ok := decodeBase64(text[loc[0]:loc[1]]); ok {
+			res = append(res, blob{EncodingBase64, decoded, loc[0], loc[1]})
+		}
+	}
+	for _, loc := range urlBlob.FindAllStringIndex(text, -1) {
+		if decoded, err := url.PathUnescape(text[loc[0]:loc[1]]); err == nil && isReadable(decoded) {
+			res = append(res, blob{EncodingURL, decoded, loc[0], loc[1]})
+		}
+	}
+	return res
+}
+
+func decodeHex(s string) (string, bool) {
+	s = strings.ReplaceAll(s, `\x`, "")
+	data, err := hex.DecodeString(s)
+	if err != nil || !isReadable(string(data)) {
+		return "", false
+	}
+	return string(data), true
+}
+
+func decodeBase64(s string) (string, bool) {
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	data, err := enc.DecodeString(strings.TrimRight(s, "="))
+	if err != nil || !isReadable(string(data)) {
+		return "", false
+	}
+	return string(data), true
+}
+
+// isReadable returns true if the decoded data is text, rather than the binary that most words and
+// identifiers decode into
+func isReadable(s string) bool {
+	if len(s) < 4 || !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// matchDecoded will run the signature over the decoded segments, returning only the matches that involve
+// decoded text. The rest of the line is matched by the regular content scan.
+func (s PatternSignature) matchDecoded(segments []decodedSegment) []decodedMatch {
+	var res []decodedMatch
+	for _, seg := range segments {
+		for _, m := range s.match.FindAllStringSubmatchIndex(seg.text, -1) {
+			if m[1] <= seg.start || m[0] >= seg.end {
+				continue
+			}
+			pm := patternMatch{match: seg.text[m[0]:m[1]], secret: seg.text[m[0]:m[1]]}
+			if s.secret > 0 && m[2*s.secret] >= 0 {
+				pm.secret = seg.text[m[2*s.secret]:m[2*s.secret+1]]
+			}
+			if s.filter.accept(strings.TrimSuffix(pm.secret, "\n")) {
+				res = append(res, decodedMatch{seg, pm})
+			}
+		}
+	}
+	return res
+}
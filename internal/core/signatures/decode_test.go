@@ -0,0 +1,65 @@
+package signatures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		depth   int
+		want    []decodedSegment
+	}{
+		{"kubernetes secret", "data:\n  password: c3VwM3JTM2NyM3Q=\n", 1, []decodedSegment{
+			{text: "  password: sup3rS3cr3t", encoding: EncodingBase64, encoded: "c3VwM3JTM2NyM3Q=", start: 12, end: 23, line: 2},
+		}},
+		{"url encoded", "DSN=postgres://app:p%40ss%2Fw0rd@db:5432/app", 1, []decodedSegment{
+			{text: "DSN=postgres://app:p@ss/w0rd@db:5432/app", encoding: EncodingURL, encoded: "DSN=postgres://app:p%40ss%2Fw0rd@db:5432/app", start: 0, end: 40, line: 1},
+		}},
+		{"hex escaped", `key = "\x74\x6f\x6b\x65\x6e\x3d\x61\x62\x63"`, 1, []decodedSegment{
+			{text: `key = "token=abc"`, encoding: EncodingHex, encoded: `\x74\x6f\x6b\x65\x6e\x3d\x61\x62\x63`, start: 7, end: 16, line: 1},
+		}},
+		{"nested", "v: " + "dG9rZW49NzQ2ZjZiNjU2ZTVmNjE2MjYz", 2, []decodedSegment{
+			{text: "v: token=746f6b656e5f616263", encoding: EncodingBase64, encoded: "dG9rZW49NzQ2ZjZiNjU2ZTVmNjE2MjYz", start: 3, end: 27, line: 1},
+			{text: "v: token=token_abc", encoding: "base64+hex", encoded: "dG9rZW49NzQ2ZjZiNjU2ZTVmNjE2MjYz", start: 9, end: 18, line: 1},
+		}},
+		{"nested beyond depth", "v: " + "dG9rZW49NzQ2ZjZiNjU2ZTVmNjE2MjYz", 1, []decodedSegment{
+			{text: "v: token=746f6b656e5f616263", encoding: EncodingBase64, encoded: "dG9rZW49NzQ2ZjZiNjU2ZTVmNjE2MjYz", start: 3, end: 27, line: 1},
+		}},
+		{"identifiers and hashes", "func NewConfigurationManager() // 9f86d081884c7d659a2feaa0c55ad015", 2, nil},
+		{"disabled", "password: c3VwM3JTM2NyM3Q=", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeContent(tt.content, tt.depth)
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPatternSignature_matchDecoded(t *testing.T) {
+	sig, err := newSignature(SignatureDef{
+		SignatureID: "password", Match: `(?i)password:\s*(?P<secret>\S{8,})`, Part: "partcontent", RejectPlaceholders: true,
+	}, patternKind)
+	require.NoError(t, err)
+
+	segments := decodeContent("password: c3VwM3JTM2NyM3Q=\npassword: Y2hhbmdlbWVwbGVhc2U=\nother: c3VwM3JTM2NyM3Q=", 2)
+	got := sig.(PatternSignature).matchDecoded(segments)
+	require.Len(t, got, 1, "the placeholder is rejected and the unrelated key doesn't match")
+	assert.Equal(t, "password: sup3rS3cr3t", got[0].match)
+	assert.Equal(t, "sup3rS3cr3t", got[0].secret)
+	assert.Equal(t, 1, got[0].segment.line)
+	assert.Equal(t, "c3VwM3JTM2NyM3Q=", got[0].segment.encoded)
+
+	// matches not involving decoded text are left to the regular content scan
+	segments = decodeContent("password: plaintextvalue c3VwM3JTM2NyM3Q=", 1)
+	assert.Empty(t, sig.(PatternSignature).matchDecoded(segments))
+}
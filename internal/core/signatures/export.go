@@ -0,0 +1,277 @@
+package signatures
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// Export formats
+const (
+	FormatYAML     = "yaml"
+	FormatGitleaks = "gitleaks"
+	FormatSemgrep  = "semgrep"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{FormatYAML, FormatGitleaks, FormatSemgrep}
+
+// Export will load and merge the signature files and return the definitions of the signatures that are
+// enabled for the given confidence level and selection, i.e. the ones that would be used during a scan.
+func Export(files []string, mLevel int, sel Selection) (SignatureConfig, error) {
+	c, err := loadSignatureSets(files)
+	if err != nil {
+		return SignatureConfig{}, err
+	}
+
+	res := SignatureConfig{Meta: c.Meta}
+	for _, set := range c.sets() {
+		for _, def := range set.defs {
+			if st, _ := status(def, set.kind, mLevel, sel); st != StatusEnabled {
+				continue
+			}
+			switch set.kind {
+			case simpleKind:
+				res.SimpleSignatures = append(res.SimpleSignatures, def)
+			case patternKind:
+				res.PatternSignatures = append(res.PatternSignatures, def)
+			case safeFunctionKind:
+				res.SafeFunctionSignatures = append(res.SafeFunctionSignatures, def)
+			}
+		}
+	}
+	return res, nil
+}
+
+// ExportYAML will write the signatures in the native format, including their tests
+func ExportYAML(c SignatureConfig) ([]byte, []LintIssue, error) {
+	data, err := yaml.Marshal(c)
+	return data, nil, err
+}
+
+// ExportGitleaks will write the signatures as a gitleaks (v8) TOML rule file. Gitleaks has no
+// notion of tests, confidence level or placeholder checks, these are reported as warnings.
+func ExportGitleaks(c SignatureConfig) ([]byte, []LintIssue, error) {
+	var warnings []LintIssue
+	warn := func(id, format string, args ...interface{}) {
+		warnings = append(warnings, LintIssue{id, LintWarning, fmt.Sprintf(format, args...)})
+	}
+
+	var gc gitleaksConfig
+	for _, set := range c.sets() {
+		if set.kind == safeFunctionKind {
+			if len(set.defs) > 0 {
+				warn("", "%d safe function signatures can't be exported to gitleaks, skipping them", len(set.defs))
+			}
+			continue
+		}
+		for _, def := range set.defs {
+			r := gitleaksRule{
+				ID:          def.SignatureID,
+				Description: def.Description,
+				Tags:        exportTags(def),
+				Entropy:     def.Entropy,
+			}
+			part := getPart(def)
+			switch {
+			case part == PartKeyValue:
+				warn(def.SignatureID, "key/value signatures can't be exported to gitleaks, skipping it")
+				continue
+			case part == PartContent:
+				r.Regex = def.Match
+				if re, err := regexp.Compile(def.Match); err == nil {
+					if i := re.SubexpIndex(secretGroup); i > 0 {
+						r.SecretGroup = i
+					}
+				}
+			case set.kind == simpleKind:
+				r.Path = simplePathRegex(part, def.Match)
+			default:
+				r.Path = patternPathRegex(part, def.Match)
+			}
+			if len(def.Stopwords) > 0 {
+				r.Allowlist = &gitleaksAllowlist{StopWords: def.Stopwords}
+			}
+			if def.EntropyCharset != "" || def.MinLength != 0 || def.MaxLength != 0 || def.RejectPlaceholders {
+				warn(def.SignatureID, "entropy-charset, min-length, max-length and reject-placeholders are not supported by gitleaks, ignoring them")
+			}
+			if len(def.Tests.Match) > 0 || len(def.Tests.NoMatch) > 0 {
+				warn(def.SignatureID, "tests are not supported by gitleaks, ignoring them")
+			}
+			if def.Verifier != nil {
+				warn(def.SignatureID, "verifiers are not supported by gitleaks, ignoring it")
+			}
+			gc.Rules = append(gc.Rules, r)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "title = %q\n\n", exportTitle(c))
+	enc := toml.NewEncoder(&buf)
+	enc.SetIndentTables(true)
+	err := enc.Encode(gc)
+	return buf.Bytes(), warnings, err
+}
+
+// semgrepRule maps to a semgrep rule using the generic language
+type semgrepRule struct {
+	ID           string                 `yaml:"id"`
+	Message      string                 `yaml:"message"`
+	Severity     string                 `yaml:"severity"`
+	PatternRegex string                 `yaml:"pattern-regex"`
+	Metadata     map[string]interface{} `yaml:"metadata,omitempty"`
+	Languages    []string               `yaml:"languages"`
+}
+
+// ExportSemgrep will write the content signatures as semgrep rules. Semgrep only matches file
+// content, so signatures of other parts can't be exported.
+func ExportSemgrep(c SignatureConfig) ([]byte, []LintIssue, error) {
+	var warnings []LintIssue
+	warn := func(id, format string, args ...interface{}) {
+		warnings = append(warnings, LintIssue{id, LintWarning, fmt.Sprintf(format, args...)})
+	}
+
+	var rules []semgrepRule
+	for _, def := range semgrepSignatures(c, warn) {
+		meta := map[string]interface{}{
+			"source":           "rvsecret",
+			"confidence-level": def.ConfidenceLevel,
+			"severity":         getSeverity(def),
+		}
+		if def.Category != "" {
+			meta["category"] = def.Category
+		}
+		if tags := exportTags(def); len(tags) > 0 {
+			meta["tags"] = tags
+		}
+		if def.Entropy != 0 || def.EntropyCharset != "" || def.MinLength != 0 || def.MaxLength != 0 || def.RejectPlaceholders || len(def.Stopwords) > 0 {
+			warn(def.SignatureID, "entropy and secret checks are not supported by semgrep, ignoring them")
+		}
+		if def.Verifier != nil {
+			warn(def.SignatureID, "verifiers are not supported by semgrep, ignoring it")
+		}
+		rules = append(rules, semgrepRule{
+			ID:           def.SignatureID,
+			Message:      def.Description,
+			Severity:     semgrepSeverity(getSeverity(def)),
+			PatternRegex: def.Match,
+			Metadata:     meta,
+			Languages:    []string{"generic"},
+		})
+	}
+
+	data, err := yaml.Marshal(struct {
+		Rules []semgrepRule `yaml:"rules"`
+	}{rules})
+	return data, warnings, err
+}
+
+// ExportSemgrepTests will write the tests of the exported semgrep rules as an annotated target
+// file, to be used with `semgrep --test`. Returns nil if there are no tests.
+func ExportSemgrepTests(c SignatureConfig) []byte {
+	var buf bytes.Buffer
+	for _, def := range semgrepSignatures(c, func(string, string, ...interface{}) {}) {
+		for _, v := range def.Tests.Match {
+			fmt.Fprintf(&buf, "# ruleid: %s\n%s\n", def.SignatureID, v)
+		}
+		for _, v := range def.Tests.NoMatch {
+			fmt.Fprintf(&buf, "# ok: %s\n%s\n", def.SignatureID, v)
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// semgrepSignatures returns the signatures that can be exported to semgrep
+func semgrepSignatures(c SignatureConfig, warn func(id, format string, args ...interface{})) []SignatureDef {
+	var res []SignatureDef
+	for _, set := range c.sets() {
+		for _, def := range set.defs {
+			switch {
+			case set.kind == safeFunctionKind:
+				warn(def.SignatureID, "safe function signatures can't be exported to semgrep, skipping it")
+				continue
+			case set.kind != patternKind || getPart(def) != PartContent:
+				warn(def.SignatureID, "semgrep only matches file content, skipping the %s signature", getPart(def))
+				continue
+			}
+			res = append(res, def)
+		}
+	}
+	return res
+}
+
+func semgrepSeverity(s string) string {
+	switch s {
+	case SeverityCritical, SeverityHigh:
+		return "ERROR"
+	case SeverityMedium:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
+
+// exportTags returns the tags of the signature, with the category and severity added as tags for the
+// formats that don't support them
+func exportTags(def SignatureDef) []string {
+	tags := append([]string{}, def.Tags...)
+	if def.Category != "" {
+		tags = append(tags, def.Category)
+	}
+	if def.Severity != "" {
+		tags = append(tags, "severity:"+getSeverity(def))
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+func exportTitle(c SignatureConfig) string {
+	if c.Meta.Version == "" {
+		return "rvsecret signatures"
+	}
+	return fmt.Sprintf("rvsecret signatures %s", c.Meta.Version)
+}
+
+// simplePathRegex will convert a simple signature, which is an exact match on a part of the path, into
+// a regular expression matching the whole path
+func simplePathRegex(part, match string) string {
+	switch part {
+	case PartFilename:
+		return `(?:^|/)` + regexp.QuoteMeta(match) + `$`
+	case PartExtension:
+		return regexp.QuoteMeta(match) + `$`
+	default:
+		return `^` + regexp.QuoteMeta(match) + `$`
+	}
+}
+
+// patternPathRegex will convert a pattern matching the filename or extension into one matching the
+// last element of the whole path
+func patternPathRegex(part, match string) string {
+	if part == PartPath {
+		return match
+	}
+	prefix, suffix := `(?:^|/)[^/]*`, `[^/]*$`
+	if strings.HasPrefix(match, "^") {
+		match = match[1:]
+		prefix = `(?:^|/)`
+		if part == PartExtension {
+			// the extension always starts right after the filename
+			prefix = ``
+		}
+	}
+	if strings.HasSuffix(match, "$") && !strings.HasSuffix(match, `\$`) {
+		match = match[:len(match)-1]
+		suffix = `$`
+	}
+	return prefix + `(?:` + match + `)` + suffix
+}
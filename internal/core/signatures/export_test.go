@@ -0,0 +1,118 @@
+package signatures
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSimplePathRegex(t *testing.T) {
+	tests := []struct {
+		part, match, path string
+		want              bool
+	}{
+		{PartFilename, "id_rsa", "id_rsa", true},
+		{PartFilename, "id_rsa", "home/.ssh/id_rsa", true},
+		{PartFilename, "id_rsa", "home/.ssh/my_id_rsa", false},
+		{PartFilename, "id_rsa", "id_rsa.pub", false},
+		{PartExtension, ".pem", "certs/server.pem", true},
+		{PartExtension, ".pem", "certs/serverxpem", false},
+		{PartPath, ".ssh/config", ".ssh/config", true},
+		{PartPath, ".ssh/config", "home/.ssh/config", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.part+" "+tt.path, func(t *testing.T) {
+			re := regexp.MustCompile(simplePathRegex(tt.part, tt.match))
+			assert.Equal(t, tt.want, re.MatchString(tt.path))
+		})
+	}
+}
+
+func TestPatternPathRegex(t *testing.T) {
+	tests := []struct {
+		part, match, path string
+		want              bool
+	}{
+		{PartFilename, `^.*\.kdbx?$`, "vault/db.kdbx", true},
+		{PartFilename, `^.*\.kdbx?$`, "db.kdb", true},
+		{PartFilename, `^.*\.kdbx?$`, "db.kdbx/readme", false},
+		{PartFilename, `secret`, "dir/my-secret.txt", true},
+		{PartFilename, `secret`, "secret/readme", false},
+		{PartExtension, `^\.p12$`, "certs/a.p12", true},
+		{PartExtension, `^\.p12$`, "certs/a.p12.bak", false},
+		{PartPath, `(?i)\.p12$`, "certs/A.P12", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.part+" "+tt.path, func(t *testing.T) {
+			re := regexp.MustCompile(patternPathRegex(tt.part, tt.match))
+			assert.Equal(t, tt.want, re.MatchString(tt.path))
+		})
+	}
+}
+
+func exportConfig() SignatureConfig {
+	return SignatureConfig{
+		SimpleSignatures: []SignatureDef{
+			{SignatureID: "idrsa", Description: "Private SSH key", Match: "id_rsa", Part: "partfilename"},
+		},
+		PatternSignatures: []SignatureDef{
+			{
+				SignatureID: "generic-password", Description: "Password assignment", Part: "partcontent",
+				Match: `password\s*=\s*"(?P<secret>[^"]+)"`, Severity: SeverityHigh, Category: "generic",
+				Stopwords: []string{"example"}, RejectPlaceholders: true, ConfidenceLevel: 3,
+				Tests: SignatureTests{Match: []string{`password = "Tq4mW9zK2xLp7vRb"`}, NoMatch: []string{`password = "changeme"`}},
+			},
+		},
+		SafeFunctionSignatures: []SignatureDef{
+			{SignatureID: "safe", Match: "getenv", Part: "partcontent"},
+		},
+	}
+}
+
+func TestExportGitleaks(t *testing.T) {
+	data, warnings, err := ExportGitleaks(exportConfig())
+	require.NoError(t, err)
+	assert.Equal(t, []LintIssue{
+		{"generic-password", LintWarning, "entropy-charset, min-length, max-length and reject-placeholders are not supported by gitleaks, ignoring them"},
+		{"generic-password", LintWarning, "tests are not supported by gitleaks, ignoring them"},
+		{"", LintWarning, "1 safe function signatures can't be exported to gitleaks, skipping them"},
+	}, warnings)
+
+	// the exported rules can be imported back
+	c, warnings, err := parseGitleaks(data)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	require.Len(t, c.PatternSignatures, 2)
+	assert.Equal(t, `(?:^|/)id_rsa$`, c.PatternSignatures[0].Match)
+	assert.Equal(t, "partpath", c.PatternSignatures[0].Part)
+	pw := c.PatternSignatures[1]
+	assert.Equal(t, `password\s*=\s*"(?P<secret>[^"]+)"`, pw.Match)
+	assert.Equal(t, []string{"example"}, pw.Stopwords)
+	assert.Equal(t, []string{"generic", "severity:high"}, pw.Tags)
+}
+
+func TestExportSemgrep(t *testing.T) {
+	data, warnings, err := ExportSemgrep(exportConfig())
+	require.NoError(t, err)
+	assert.Equal(t, []LintIssue{
+		{"idrsa", LintWarning, "semgrep only matches file content, skipping the filename signature"},
+		{"safe", LintWarning, "safe function signatures can't be exported to semgrep, skipping it"},
+		{"generic-password", LintWarning, "entropy and secret checks are not supported by semgrep, ignoring them"},
+	}, warnings)
+
+	var out struct {
+		Rules []semgrepRule `yaml:"rules"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	require.Len(t, out.Rules, 1)
+	assert.Equal(t, "generic-password", out.Rules[0].ID)
+	assert.Equal(t, "ERROR", out.Rules[0].Severity)
+	assert.Equal(t, []string{"generic"}, out.Rules[0].Languages)
+
+	assert.Equal(t, "# ruleid: generic-password\npassword = \"Tq4mW9zK2xLp7vRb\"\n# ok: generic-password\npassword = \"changeme\"\n",
+		string(ExportSemgrepTests(exportConfig())))
+	assert.Nil(t, ExportSemgrepTests(SignatureConfig{}))
+}
@@ -0,0 +1,162 @@
+package signatures
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/rumenvasilev/rvsecret/internal/config"
+)
+
+// gitleaksConfig maps to a gitleaks (v8) TOML rule file
+type gitleaksConfig struct {
+	Extend    map[string]interface{} `toml:"extend,omitempty"`
+	Allowlist *gitleaksAllowlist     `toml:"allowlist,omitempty"`
+	Rules     []gitleaksRule         `toml:"rules"`
+}
+
+type gitleaksRule struct {
+	ID          string              `toml:"id"`
+	Description string              `toml:"description,omitempty"`
+	Regex       string              `toml:"regex,omitempty"`
+	Path        string              `toml:"path,omitempty"`
+	Allowlist   *gitleaksAllowlist  `toml:"allowlist,omitempty"`
+	Keywords    []string            `toml:"keywords,omitempty"`
+	Tags        []string            `toml:"tags,omitempty"`
+	Allowlists  []gitleaksAllowlist `toml:"allowlists,omitempty"`
+	Entropy     float64             `toml:"entropy,omitempty"`
+	SecretGroup int                 `toml:"secretGroup,omitempty"`
+}
+
+type gitleaksAllowlist struct {
+	Commits   []string `toml:"commits,omitempty"`
+	Paths     []string `toml:"paths,omitempty"`
+	Regexes   []string `toml:"regexes,omitempty"`
+	StopWords []string `toml:"stopwords,omitempty"`
+}
+
+// parseGitleaks will convert a gitleaks rule file into signatures. Rule options that can't be expressed
+// as a signature are dropped and reported as warnings.
+func parseGitleaks(data []byte) (SignatureConfig, []LintIssue, error) {
+	var gc gitleaksConfig
+	if err := toml.Unmarshal(data, &gc); err != nil {
+		return SignatureConfig{}, nil, err
+	}
+
+	var c SignatureConfig
+	var warnings []LintIssue
+	warn := func(id, format string, args ...interface{}) {
+		warnings = append(warnings, LintIssue{id, LintWarning, fmt.Sprintf(format, args...)})
+	}
+	if len(gc.Extend) > 0 {
+		warn("", "extending another gitleaks configuration is not supported, only the rules in this file are imported")
+	}
+	if gc.Allowlist != nil {
+		warn("", "the global allowlist is not supported, ignoring it")
+	}
+
+	for _, r := range gc.Rules {
+		def := SignatureDef{
+			SignatureID:     r.ID,
+			Description:     r.Description,
+			Comment:         "imported from gitleaks",
+			Tags:            r.Tags,
+			Entropy:         r.Entropy,
+			Enable:          1,
+			ConfidenceLevel: config.DefaultConfig.Global.ConfidenceLevel,
+		}
+		if def.Description == "" {
+			def.Description = r.ID
+		}
+
+		switch {
+		case r.Regex != "":
+			def.Part = "partcontent"
+			group := r.SecretGroup
+			if group == 0 && captures(r.Regex) == 1 {
+				// gitleaks reports the only capture group as the secret
+				group = 1
+			}
+			match, err := withSecretGroup(r.Regex, group)
+			if err != nil {
+				warn(r.ID, "%s, using the whole match as the secret", err.Error())
+				match = r.Regex
+			}
+			def.Match = match
+			if r.Path != "" {
+				warn(r.ID, "the path condition %q can't be combined with a content match, ignoring it", r.Path)
+			}
+		case r.Path != "":
+			def.Part = "partpath"
+			def.Match = r.Path
+		default:
+			warn(r.ID, "rule has neither regex nor path, skipping it")
+			continue
+		}
+
+		if len(r.Keywords) > 0 {
+			warn(r.ID, "keywords are not supported, the regex is matched against every file")
+		}
+		allowlists := r.Allowlists
+		if r.Allowlist != nil {
+			allowlists = append(allowlists, *r.Allowlist)
+		}
+		for _, a := range allowlists {
+			def.Stopwords = append(def.Stopwords, a.StopWords...)
+			if len(a.Regexes) > 0 || len(a.Paths) > 0 || len(a.Commits) > 0 {
+				warn(r.ID, "allowlist regexes, paths and commits are not supported, only stopwords are imported")
+			}
+		}
+
+		c.PatternSignatures = append(c.PatternSignatures, def)
+	}
+	return c, warnings, nil
+}
+
+// withSecretGroup will rename the capture group with the given index to the secret group, so that it's the one
+// checked for entropy. A zero index keeps the expression as is. The expression is edited in place, rather than
+// re-generated from its parsed form, to keep it readable.
+func withSecretGroup(expr string, group int) (string, error) {
+	if group == 0 {
+		return expr, nil
+	}
+	if _, err := syntax.Parse(expr, syntax.Perl); err != nil {
+		return "", fmt.Errorf("invalid regular expression, %w", err)
+	}
+
+	var cnt int
+	inClass := false
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\':
+			i++ // skip the escaped character
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+			// a leading ] or ^] is part of the class
+			if strings.HasPrefix(expr[i+1:], "]") {
+				i++
+			} else if strings.HasPrefix(expr[i+1:], "^]") {
+				i += 2
+			}
+		case c == '(':
+			rest := expr[i+1:]
+			var name string
+			switch {
+			case strings.HasPrefix(rest, "?P<"):
+				name = rest[:strings.IndexByte(rest, '>')+1]
+			case strings.HasPrefix(rest, "?<"):
+				name = rest[:strings.IndexByte(rest, '>')+1]
+			case strings.HasPrefix(rest, "?"):
+				continue // non-capturing group or flags
+			}
+			cnt++
+			if cnt == group {
+				return expr[:i+1] + "?P<" + secretGroup + ">" + rest[len(name):], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("secretGroup %d doesn't exist", group)
+}
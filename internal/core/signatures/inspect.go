@@ -0,0 +1,158 @@
+package signatures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Signature states as reported by Inspect
+const (
+	StatusEnabled    = "enabled"
+	StatusDisabled   = "disabled"    // enable is set to 0 in the signatures file or disabled by the user
+	StatusBelowLevel = "below-level" // confidence level is lower than the requested one
+	StatusFiltered   = "filtered"    // severity is lower than the requested one or none of the requested tags are set
+	StatusInvalid    = "invalid"     // the signature doesn't pass validation
+)
+
+// SignatureInfo describes a signature definition and whether it would be used during a scan
+type SignatureInfo struct {
+	ID              string   `json:"id"`
+	Kind            string   `json:"kind"`
+	Part            string   `json:"part"`
+	Description     string   `json:"description"`
+	Comment         string   `json:"comment,omitempty"`
+	Severity        string   `json:"severity"`
+	Category        string   `json:"category,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Key             string   `json:"key,omitempty"`
+	Match           string   `json:"match"`
+	Status          string   `json:"status"`
+	Source          string   `json:"source"`
+	Issues          []string `json:"issues,omitempty"`
+	Entropy         float64  `json:"entropy"`
+	ConfidenceLevel int      `json:"confidence-level"`
+	Tests           int      `json:"tests"`
+
+	EntropyCharset     string   `json:"entropy-charset,omitempty"`
+	Stopwords          []string `json:"stopwords,omitempty"`
+	MinLength          int      `json:"min-length,omitempty"`
+	MaxLength          int      `json:"max-length,omitempty"`
+	RejectPlaceholders bool     `json:"reject-placeholders,omitempty"`
+	Verifier           string   `json:"verifier,omitempty"`
+}
+
+// Enabled returns true if the signature will be used during a scan
+func (i SignatureInfo) Enabled() bool {
+	return i.Status == StatusEnabled
+}
+
+// InspectStats holds the number of signatures found in the signature files, grouped by status
+type InspectStats struct {
+	Total      int `json:"total"`
+	Enabled    int `json:"enabled"`
+	Disabled   int `json:"disabled"`
+	BelowLevel int `json:"below-level"`
+	Filtered   int `json:"filtered"`
+	Invalid    int `json:"invalid"`
+}
+
+func (s InspectStats) String() string {
+	return fmt.Sprintf("%d out of %d signatures enabled (%d disabled, %d below confidence level, %d filtered, %d invalid)",
+		s.Enabled, s.Total, s.Disabled, s.BelowLevel, s.Filtered, s.Invalid)
+}
+
+func (k signatureKind) String() string {
+	switch k {
+	case simpleKind:
+		return "simple"
+	case patternKind:
+		return "pattern"
+	case safeFunctionKind:
+		return "safe"
+	case builtinKind:
+		return "builtin"
+	default:
+		return "unknown"
+	}
+}
+
+// Inspect will load and merge the signature files and describe every signature in them, using the same
+// rules as Load to decide whether it is enabled for the given confidence level and selection.
+func Inspect(files []string, mLevel int, sel Selection) ([]SignatureInfo, InspectStats, error) {
+	c, err := loadSignatureSets(files)
+	if err != nil {
+		return nil, InspectStats{}, err
+	}
+	res, stats := inspect(c, mLevel, sel)
+	return res, stats, nil
+}
+
+func inspect(c SignatureConfig, mLevel int, sel Selection) ([]SignatureInfo, InspectStats) {
+	var res []SignatureInfo
+	var stats InspectStats
+	for _, set := range append(c.sets(), signatureSet{builtinSignatures, builtinKind}) {
+		for _, def := range set.defs {
+			info := describe(def, set.kind, mLevel, sel)
+			switch info.Status {
+			case StatusEnabled:
+				stats.Enabled++
+			case StatusDisabled:
+				stats.Disabled++
+			case StatusBelowLevel:
+				stats.BelowLevel++
+			case StatusFiltered:
+				stats.Filtered++
+			case StatusInvalid:
+				stats.Invalid++
+			}
+			stats.Total++
+			res = append(res, info)
+		}
+	}
+	return res, stats
+}
+
+func describe(def SignatureDef, kind signatureKind, mLevel int, sel Selection) SignatureInfo {
+	info := SignatureInfo{
+		ID:              def.SignatureID,
+		Kind:            kind.String(),
+		Part:            getPart(def),
+		Description:     def.Description,
+		Comment:         def.Comment,
+		Severity:        getSeverity(def),
+		Category:        def.Category,
+		Tags:            def.Tags,
+		Key:             def.Key,
+		Match:           def.Match,
+		Source:          def.Source,
+		Entropy:         def.Entropy,
+		ConfidenceLevel: def.ConfidenceLevel,
+		Tests:           len(def.Tests.Match) + len(def.Tests.NoMatch),
+
+		EntropyCharset:     def.EntropyCharset,
+		Stopwords:          def.Stopwords,
+		MinLength:          def.MinLength,
+		MaxLength:          def.MaxLength,
+		RejectPlaceholders: def.RejectPlaceholders,
+	}
+	if def.Verifier != nil {
+		info.Verifier = fmt.Sprintf("%s %s", def.Verifier.Type, def.Verifier.Endpoint)
+	}
+
+	var issues []LintIssue
+	info.Status, issues = status(def, kind, mLevel, sel)
+	for _, v := range issues {
+		info.Issues = append(info.Issues, fmt.Sprintf("%s: %s", v.Level, v.Message))
+	}
+	return info
+}
+
+// Find returns the signature with the given id, ids are matched case-insensitively
+func Find(infos []SignatureInfo, id string) (SignatureInfo, bool) {
+	for _, v := range infos {
+		if strings.EqualFold(v.ID, id) {
+			return v, true
+		}
+	}
+	return SignatureInfo{}, false
+}
@@ -0,0 +1,125 @@
+package signatures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	c := SignatureConfig{
+		SimpleSignatures: []SignatureDef{
+			{SignatureID: "idrsa", Description: "key", Match: "id_rsa", Part: "partfilename", Enable: 1, ConfidenceLevel: 3},
+		},
+		PatternSignatures: []SignatureDef{
+			{SignatureID: "aws", Description: "aws", Match: `AKIA[0-9A-Z]{16}`, Part: "partcontent", Enable: 1, ConfidenceLevel: 5},
+			{SignatureID: "low", Description: "low", Match: "password", Part: "partcontent", Enable: 1, ConfidenceLevel: 1},
+			{SignatureID: "off", Description: "off", Match: "secret", Part: "partcontent", Enable: 0, ConfidenceLevel: 5},
+			{SignatureID: "broken", Description: "broken", Match: "(abc", Part: "partcontent", Enable: 0, ConfidenceLevel: 1},
+		},
+	}
+
+	infos, stats := inspect(c, 3, Selection{})
+	got := make(map[string]string)
+	for _, v := range infos {
+		got[v.ID] = v.Status
+	}
+	assert.Equal(t, map[string]string{
+		"idrsa":  StatusEnabled,
+		"aws":    StatusEnabled,
+		"low":    StatusBelowLevel,
+		"off":    StatusDisabled,
+		"broken": StatusInvalid,
+
+		PrivateKeySignatureID: StatusEnabled,
+	}, got)
+	assert.Equal(t, InspectStats{Total: 6, Enabled: 3, Disabled: 1, BelowLevel: 1, Invalid: 1}, stats)
+
+	info, ok := Find(infos, "IDRSA")
+	assert.True(t, ok)
+	assert.Equal(t, "simple", info.Kind)
+	assert.Equal(t, PartFilename, info.Part)
+
+	info, ok = Find(infos, PrivateKeySignatureID)
+	assert.True(t, ok)
+	assert.Equal(t, "builtin", info.Kind)
+	assert.Equal(t, BuiltinSource, info.Source)
+
+	_, ok = Find(infos, "missing")
+	assert.False(t, ok)
+}
+
+func TestSelection(t *testing.T) {
+	defs := []SignatureDef{
+		{SignatureID: "aws-key", Description: "AWS Access Key ID", Enable: 1, ConfidenceLevel: 5},
+		{SignatureID: "low", Description: "Username/password pair", Enable: 1, ConfidenceLevel: 1},
+		{SignatureID: "off", Description: "Disabled rule", Enable: 0, ConfidenceLevel: 5},
+	}
+	tests := []struct {
+		name string
+		sel  Selection
+		want []string
+	}{
+		{"defaults", Selection{}, []string{StatusEnabled, StatusBelowLevel, StatusDisabled}},
+		{"disable by id", Selection{Disable: []string{"AWS-KEY"}}, []string{StatusDisabled, StatusBelowLevel, StatusDisabled}},
+		{"disable by glob", Selection{Disable: []string{"aws-*"}}, []string{StatusDisabled, StatusBelowLevel, StatusDisabled}},
+		{"enable below level", Selection{Enable: []string{"low"}}, []string{StatusEnabled, StatusEnabled, StatusDisabled}},
+		{"enable by description", Selection{Enable: []string{"*/password*", "disabled ?ule"}}, []string{StatusEnabled, StatusEnabled, StatusEnabled}},
+		{"disable wins", Selection{Enable: []string{"*"}, Disable: []string{"*aws*"}}, []string{StatusDisabled, StatusEnabled, StatusEnabled}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, def := range defs {
+				got = append(got, selected(def, 3, tt.sel))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelection_severityAndTags(t *testing.T) {
+	defs := []SignatureDef{
+		{SignatureID: "aws-key", Severity: "critical", Category: "cloud", Tags: []string{"aws"}, Enable: 1, ConfidenceLevel: 5},
+		{SignatureID: "db-url", Severity: "High", Category: "database", Enable: 1, ConfidenceLevel: 5},
+		{SignatureID: "generic", Tags: []string{"generic"}, Enable: 1, ConfidenceLevel: 5},
+	}
+	tests := []struct {
+		name string
+		sel  Selection
+		want []string
+	}{
+		{"min severity", Selection{Severity: SeverityHigh}, []string{StatusEnabled, StatusEnabled, StatusFiltered}},
+		{"default severity", Selection{Severity: SeverityMedium}, []string{StatusEnabled, StatusEnabled, StatusEnabled}},
+		{"tags and categories", Selection{Tags: []string{"aws", "data*"}}, []string{StatusEnabled, StatusEnabled, StatusFiltered}},
+		{"severity and tags", Selection{Severity: SeverityCritical, Tags: []string{"database"}}, []string{StatusFiltered, StatusFiltered, StatusFiltered}},
+		{"enable overrides filters", Selection{Severity: SeverityCritical, Enable: []string{"generic"}}, []string{StatusEnabled, StatusFiltered, StatusEnabled}},
+		{"disable by category", Selection{Disable: []string{"cloud"}}, []string{StatusDisabled, StatusEnabled, StatusEnabled}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, def := range defs {
+				got = append(got, selected(def, 3, tt.sel))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := ParseSeverity(" HIGH ")
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityHigh, s)
+
+	s, err = ParseSeverity("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", s)
+
+	_, err = ParseSeverity("urgent")
+	assert.EqualError(t, err, `unknown severity "urgent", must be one of info, low, medium, high, critical`)
+
+	assert.True(t, SeverityAtLeast(SeverityCritical, SeverityHigh))
+	assert.True(t, SeverityAtLeast(SeverityInfo, ""))
+	assert.False(t, SeverityAtLeast(SeverityMedium, SeverityHigh))
+}
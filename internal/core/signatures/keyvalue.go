@@ -0,0 +1,94 @@
+package signatures
+
+import (
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/core/keyvalue"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+)
+
+// keyValue is a pair of a structured file, the value possibly decoded from an encoded one
+type keyValue struct {
+	encoding string
+	encoded  string
+	keyvalue.Pair
+}
+
+// pairMatch is a match of a pattern against the value of a pair
+type pairMatch struct {
+	pair keyValue
+	patternMatch
+}
+
+// parsePairs will extract the key/value pairs of a structured file. Values that are entirely encoded, e.g. the
+// data of Kubernetes secrets, are also reported decoded.
+func parsePairs(path, content string, decodeDepth int) []keyValue {
+	pairs, err := keyvalue.Parse(path, []byte(content))
+	if err != nil {
+		log.Log.Debug("Failed parsing %s as %s, %s", path, keyvalue.Format(path), err.Error())
+	}
+	return withDecodedPairs(pairs, decodeDepth)
+}
+
+func withDecodedPairs(pairs []keyvalue.Pair, decodeDepth int) []keyValue {
+	var res []keyValue
+	for _, p := range pairs {
+		res = append(res, keyValue{Pair: p})
+		for _, seg := range decodeContent(p.Value, decodeDepth) {
+			if seg.start != 0 || seg.end != len(seg.text) || seg.line != 1 {
+				// only part of the value is encoded
+				continue
+			}
+			decoded := p
+			decoded.Value = seg.text
+			res = append(res, keyValue{encoding: seg.encoding, encoded: seg.encoded, Pair: decoded})
+		}
+	}
+	return res
+}
+
+// matchPairs will run the signature over the pairs whose key matches the key expression of the signature
+func (s PatternSignature) matchPairs(pairs []keyValue) []pairMatch {
+	var res []pairMatch
+	for _, p := range pairs {
+		if s.key != nil && !s.key.MatchString(p.Key) {
+			continue
+		}
+		m := s.match.FindStringSubmatchIndex(p.Value)
+		if m == nil {
+			continue
+		}
+		pm := patternMatch{match: p.Value[m[0]:m[1]], secret: p.Value[m[0]:m[1]]}
+		if s.secret > 0 && m[2*s.secret] >= 0 {
+			pm.secret = p.Value[m[2*s.secret]:m[2*s.secret+1]]
+		}
+		if s.filter.accept(pm.secret) {
+			res = append(res, pairMatch{p, pm})
+		}
+	}
+	return res
+}
+
+// discoverPairs will match the signature against the key/value pairs of the file
+func discoverPairs(sig Signature, pairs []keyValue, hideSecrets bool) []DiscoverOutput {
+	ps, ok := sig.(PatternSignature)
+	if !ok || ps.part != PartKeyValue || len(pairs) == 0 {
+		return nil
+	}
+	var res []DiscoverOutput
+	for _, m := range ps.matchPairs(pairs) {
+		out := DiscoverOutput{
+			Sig:      sig,
+			Secret:   m.secret,
+			Encoding: m.pair.encoding,
+			LineNum:  m.pair.Line,
+			Column:   m.pair.Column,
+		}
+		if !hideSecrets {
+			out.Content = m.pair.Key + ": " + strings.TrimSuffix(m.match, "\n")
+			out.Encoded = m.pair.encoded
+		}
+		res = append(res, out)
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package signatures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverPairs(t *testing.T) {
+	sig, err := newSignature(SignatureDef{
+		SignatureID: "config-password", Part: "partkeyvalue", Key: `(?i)(^|[._])password$`, Match: `^.{8,}$`, RejectPlaceholders: true,
+	}, patternKind)
+	require.NoError(t, err)
+
+	content := `apiVersion: v1
+kind: Secret
+data:
+  DB_PASSWORD: c3VwM3JTM2NyM3RWYWx1ZQ==
+stringData:
+  admin_password: "${ADMIN_PASSWORD}"
+  password_hint: rememberthehorse
+`
+	got := discoverPairs(sig, parsePairs("secret.yaml", content, 2), false)
+	assert.Equal(t, []DiscoverOutput{
+		{Sig: sig, Content: "data.DB_PASSWORD: c3VwM3JTM2NyM3RWYWx1ZQ==", Secret: "c3VwM3JTM2NyM3RWYWx1ZQ==", LineNum: 4, Column: 16},
+		{Sig: sig, Content: "data.DB_PASSWORD: sup3rS3cr3tValue", Secret: "sup3rS3cr3tValue", Encoding: EncodingBase64, Encoded: "c3VwM3JTM2NyM3RWYWx1ZQ==", LineNum: 4, Column: 16},
+	}, got)
+
+	hidden := discoverPairs(sig, parsePairs("secret.yaml", content, 0), true)
+	require.Len(t, hidden, 1)
+	assert.Empty(t, hidden[0].Content)
+	assert.Equal(t, "c3VwM3JTM2NyM3RWYWx1ZQ==", hidden[0].Secret)
+
+	assert.Empty(t, discoverPairs(sig, parsePairs("main.go", `password := "c3VwM3JTM2NyM3RWYWx1ZQ=="`, 2), false))
+}
+
+func TestRunTests_keyValue(t *testing.T) {
+	c := SignatureConfig{PatternSignatures: []SignatureDef{{
+		SignatureID: "config-password", Part: "partkeyvalue", Key: `(?i)password$`, Match: `(?P<secret>.{8,})`,
+		Tests: SignatureTests{
+			Match:   []string{"spring.datasource.password: s3cr3tvalue", "DB_PASSWORD=s3cr3tvalue"},
+			NoMatch: []string{"spring.datasource.username: s3cr3tvalue", "password: short"},
+		},
+	}}}
+	report, err := RunTests(c)
+	require.NoError(t, err)
+	assert.Len(t, report.Results, 4)
+	assert.Equal(t, 0, report.Failed())
+}
@@ -0,0 +1,271 @@
+package signatures
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/core/verify"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// Lint levels, an error renders the signature unusable and it won't be loaded, except for the ones of validateStrict
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+const (
+	minConfidenceLevel = 1
+	maxConfidenceLevel = 5
+	// maxProgramSize is the number of compiled regex instructions above which matching gets slow
+	maxProgramSize = 5000
+)
+
+// LintIssue describes a problem found in a signature definition
+type LintIssue struct {
+	SignatureID string
+	Level       string
+	Message     string
+}
+
+func (i LintIssue) String() string {
+	if i.SignatureID == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s: %s", i.SignatureID, i.Message)
+}
+
+// LintFile will load the signatures file and report any problems found in it
+func LintFile(filePath string) ([]LintIssue, error) {
+	fp, err := util.SetHomeDir(strings.TrimSpace(filePath))
+	if err != nil {
+		return nil, err
+	}
+	c, err := loadSignatureSet(fp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signatures file %s: %w", filePath, err)
+	}
+	return Lint(c), nil
+}
+
+// Lint will validate every signature in the set, regardless of whether it is enabled or not
+func Lint(c SignatureConfig) []LintIssue {
+	var issues []LintIssue
+	seen := make(map[string]bool)
+	for _, set := range c.sets() {
+		for _, def := range set.defs {
+			issues = append(issues, validate(def, set.kind)...)
+			issues = append(issues, validateStrict(def, set.kind)...)
+			if def.SignatureID == "" {
+				continue
+			}
+			if seen[def.SignatureID] {
+				issues = append(issues, LintIssue{def.SignatureID, LintError, "duplicate signatureid"})
+			}
+			seen[def.SignatureID] = true
+		}
+	}
+	return issues
+}
+
+// HasErrors returns true if any of the issues prevents a signature from being used
+func HasErrors(issues []LintIssue) bool {
+	for _, v := range issues {
+		if v.Level == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+// validate will check a single signature definition for problems
+func validate(def SignatureDef, kind signatureKind) []LintIssue {
+	var issues []LintIssue
+	add := func(level, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{def.SignatureID, level, fmt.Sprintf(format, args...)})
+	}
+
+	if def.SignatureID == "" {
+		add(LintError, "missing signatureid (description %q)", def.Description)
+	}
+	if strings.TrimSpace(def.Description) == "" {
+		add(LintWarning, "missing description")
+	}
+	if def.ConfidenceLevel < minConfidenceLevel || def.ConfidenceLevel > maxConfidenceLevel {
+		add(LintWarning, "confidence-level %d is out of range [%d-%d]", def.ConfidenceLevel, minConfidenceLevel, maxConfidenceLevel)
+	}
+
+	if _, err := ParseSeverity(def.Severity); err != nil {
+		add(LintError, "%s", err.Error())
+	}
+
+	part := strings.ToLower(def.Part)
+	switch part {
+	case "":
+		add(LintWarning, "part is not set, defaulting to %s", PartContent)
+	case "partpath", "partfilename", "partextension", "partcontent", "partkeyvalue":
+	default:
+		add(LintError, "unknown part %q, must be one of partpath, partfilename, partextension, partcontent or partkeyvalue", def.Part)
+	}
+	if def.Key != "" {
+		if _, err := regexp.Compile(def.Key); err != nil {
+			add(LintError, "invalid key expression, %s", err.Error())
+		}
+		if part != "partkeyvalue" {
+			add(LintWarning, "key only applies to partkeyvalue signatures")
+		}
+	}
+
+	issues = append(issues, validateSecretOptions(def, kind)...)
+	if def.Verifier != nil {
+		if err := verify.Validate(*def.Verifier); err != nil {
+			add(LintError, "invalid verifier, %s", err.Error())
+		}
+		if kind != patternKind || (part != "" && part != "partcontent" && part != "partkeyvalue") {
+			add(LintWarning, "verifier only applies to pattern signatures matching file content or key/value pairs")
+		}
+	}
+
+	if kind == builtinKind {
+		// implemented in code, there's no expression to check
+		return issues
+	}
+	if def.Match == "" {
+		add(LintError, "missing match expression")
+		return issues
+	}
+	if kind == simpleKind {
+		return issues
+	}
+
+	if _, err := regexp.Compile(def.Match); err != nil {
+		add(LintError, "invalid regular expression, %s", err.Error())
+		return issues
+	}
+	if msg := slowPattern(def.Match); msg != "" {
+		add(LintWarning, "%s", msg)
+	}
+	return issues
+}
+
+// validateStrict will check for mistakes that signatures used to be loaded with. They fail the lint,
+// while loading the signatures only warns about them so that the signature files keep working.
+func validateStrict(def SignatureDef, kind signatureKind) []LintIssue {
+	var issues []LintIssue
+	add := func(format string, args ...interface{}) {
+		issues = append(issues, LintIssue{def.SignatureID, LintError, fmt.Sprintf(format, args...)})
+	}
+
+	part := strings.ToLower(def.Part)
+	if kind == simpleKind && (part == "" || part == "partcontent" || part == "partkeyvalue") {
+		add("simple signatures cannot match file content")
+	}
+	if kind == simpleKind || kind == builtinKind || def.Match == "" {
+		return issues
+	}
+	if re, err := regexp.Compile(def.Match); err == nil && re.MatchString("") {
+		add("regular expression matches an empty string")
+	}
+	return issues
+}
+
+// loadIssues will validate the signature the way it is loaded for a scan, with the strict checks as warnings
+func loadIssues(def SignatureDef, kind signatureKind) []LintIssue {
+	issues := validate(def, kind)
+	for _, v := range validateStrict(def, kind) {
+		v.Level = LintWarning
+		issues = append(issues, v)
+	}
+	return issues
+}
+
+// validateSecretOptions will check the options applied to the secret of every match
+func validateSecretOptions(def SignatureDef, kind signatureKind) []LintIssue {
+	var issues []LintIssue
+	add := func(level, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{def.SignatureID, level, fmt.Sprintf(format, args...)})
+	}
+
+	if def.EntropyCharset != "" {
+		if _, ok := charsets[strings.ToLower(def.EntropyCharset)]; !ok {
+			add(LintError, "unknown entropy-charset %q, must be one of %s, %s or %s", def.EntropyCharset, CharsetHex, CharsetBase64, CharsetAlphanumeric)
+		}
+	}
+	if def.MinLength < 0 || def.MaxLength < 0 {
+		add(LintError, "min-length and max-length cannot be negative")
+	}
+	if def.MaxLength > 0 && def.MinLength > def.MaxLength {
+		add(LintError, "min-length %d is greater than max-length %d", def.MinLength, def.MaxLength)
+	}
+	hasOptions := def.EntropyCharset != "" || def.MinLength != 0 || def.MaxLength != 0 || def.RejectPlaceholders || len(def.Stopwords) > 0
+	if kind != patternKind && hasOptions {
+		add(LintWarning, "entropy-charset, min-length, max-length, reject-placeholders and stopwords only apply to pattern signatures")
+	}
+	return issues
+}
+
+// slowPattern will return a description of why the expression is expected to be slow to evaluate, or
+// an empty string if it isn't. Go's regexp engine guarantees linear time, but the cost of every
+// byte grows with the size of the compiled program and the number of ambiguous paths through it.
+func slowPattern(expr string) string {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+	if hasNestedRepeat(re) {
+		return "nested repetition of a repeated expression, e.g. (a+)+, this is very slow to evaluate"
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return ""
+	}
+	if len(prog.Inst) > maxProgramSize {
+		return fmt.Sprintf("regular expression compiles into %d instructions (max %d), this is very slow to evaluate", len(prog.Inst), maxProgramSize)
+	}
+	return ""
+}
+
+// hasNestedRepeat will look for unbounded repetitions whose body is itself only made of repetitions
+func hasNestedRepeat(re *syntax.Regexp) bool {
+	if isUnboundedRepeat(re) && onlyRepeats(re.Sub[0]) {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasNestedRepeat(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func onlyRepeats(re *syntax.Regexp) bool {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		return true
+	case syntax.OpConcat, syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !onlyRepeats(sub) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isUnboundedRepeat(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return true
+	case syntax.OpRepeat:
+		return re.Max == -1
+	default:
+		return false
+	}
+}
@@ -0,0 +1,108 @@
+package signatures
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/core/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	valid := SignatureDef{SignatureID: "ok", Description: "valid", Match: `AKIA[0-9A-Z]{16}`, Part: "partcontent", Enable: 1, ConfidenceLevel: 3}
+	tests := []struct {
+		name   string
+		mutate func(*SignatureDef)
+		kind   signatureKind
+		want   []LintIssue
+	}{
+		{"valid", func(d *SignatureDef) {}, patternKind, nil},
+		{"invalid regex", func(d *SignatureDef) { d.Match = "(abc" }, patternKind, []LintIssue{{"ok", LintError, "invalid regular expression, error parsing regexp: missing closing ): `(abc`"}}},
+		{"unknown part", func(d *SignatureDef) { d.Part = "body" }, patternKind, []LintIssue{{"ok", LintError, `unknown part "body", must be one of partpath, partfilename, partextension, partcontent or partkeyvalue`}}},
+		{"missing description", func(d *SignatureDef) { d.Description = "" }, patternKind, []LintIssue{{"ok", LintWarning, "missing description"}}},
+		{"confidence level", func(d *SignatureDef) { d.ConfidenceLevel = 7 }, patternKind, []LintIssue{{"ok", LintWarning, "confidence-level 7 is out of range [1-5]"}}},
+		{"matches empty string", func(d *SignatureDef) { d.Match = "(password)?" }, patternKind, []LintIssue{{"ok", LintError, "regular expression matches an empty string"}}},
+		{"nested repetition", func(d *SignatureDef) { d.Match = `key=(\w+\s*)+;` }, patternKind, []LintIssue{{"ok", LintWarning, "nested repetition of a repeated expression, e.g. (a+)+, this is very slow to evaluate"}}},
+		{"simple content", func(d *SignatureDef) { d.Match = "id_rsa" }, simpleKind, []LintIssue{{"ok", LintError, "simple signatures cannot match file content"}}},
+		{"unknown charset", func(d *SignatureDef) { d.EntropyCharset = "base32" }, patternKind, []LintIssue{{"ok", LintError, `unknown entropy-charset "base32", must be one of hex, base64 or alphanumeric`}}},
+		{"length range", func(d *SignatureDef) { d.MinLength, d.MaxLength = 20, 10 }, patternKind, []LintIssue{{"ok", LintError, "min-length 20 is greater than max-length 10"}}},
+		{"secret options on simple", func(d *SignatureDef) { d.Part, d.RejectPlaceholders = "partfilename", true }, simpleKind, []LintIssue{{"ok", LintWarning, "entropy-charset, min-length, max-length, reject-placeholders and stopwords only apply to pattern signatures"}}},
+		{"valid verifier", func(d *SignatureDef) {
+			d.Verifier = &verify.Definition{Type: "http", Endpoint: "https://api.example.com"}
+		}, patternKind, nil},
+		{"invalid verifier", func(d *SignatureDef) { d.Verifier = &verify.Definition{Type: "smtp", Endpoint: "localhost:25"} }, patternKind, []LintIssue{{"ok", LintError, `invalid verifier, unknown verifier type "smtp", expected one of http, tcp`}}},
+		{"verifier on filename", func(d *SignatureDef) {
+			d.Part, d.Verifier = "partfilename", &verify.Definition{Type: "http", Endpoint: "https://api.example.com"}
+		}, patternKind, []LintIssue{{"ok", LintWarning, "verifier only applies to pattern signatures matching file content or key/value pairs"}}},
+		{"key value", func(d *SignatureDef) { d.Part, d.Key = "partkeyvalue", `(?i)password$` }, patternKind, nil},
+		{"invalid key", func(d *SignatureDef) { d.Part, d.Key = "partkeyvalue", "(abc" }, patternKind, []LintIssue{{"ok", LintError, "invalid key expression, error parsing regexp: missing closing ): `(abc`"}}},
+		{"key on content", func(d *SignatureDef) { d.Key = "password" }, patternKind, []LintIssue{{"ok", LintWarning, "key only applies to partkeyvalue signatures"}}},
+		{"simple key value", func(d *SignatureDef) { d.Part = "partkeyvalue" }, simpleKind, []LintIssue{{"ok", LintError, "simple signatures cannot match file content"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := valid
+			tt.mutate(&def)
+			assert.Equal(t, tt.want, append(validate(def, tt.kind), validateStrict(def, tt.kind)...))
+		})
+	}
+}
+
+func TestLoadIssues(t *testing.T) {
+	def := SignatureDef{SignatureID: "optional", Description: "optional", Match: "(password)?", Part: "partcontent", Enable: 1, ConfidenceLevel: 3}
+	assert.Equal(t, []LintIssue{{"optional", LintError, "regular expression matches an empty string"}}, Lint(SignatureConfig{PatternSignatures: []SignatureDef{def}}))
+	assert.Equal(t, []LintIssue{{"optional", LintWarning, "regular expression matches an empty string"}}, loadIssues(def, patternKind))
+
+	def = SignatureDef{SignatureID: "content", Description: "content", Match: "id_rsa", Part: "partcontent", Enable: 1, ConfidenceLevel: 3}
+	assert.Equal(t, []LintIssue{{"content", LintWarning, "simple signatures cannot match file content"}}, loadIssues(def, simpleKind))
+}
+
+func TestLint_duplicates(t *testing.T) {
+	def := SignatureDef{SignatureID: "dup", Description: "dup", Match: "abc", Part: "partcontent", ConfidenceLevel: 1}
+	issues := Lint(SignatureConfig{PatternSignatures: []SignatureDef{def, def}})
+	assert.Equal(t, []LintIssue{{"dup", LintError, "duplicate signatureid"}}, issues)
+	assert.True(t, HasErrors(issues))
+}
+
+func TestLoad_skipsInvalidSignatures(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "signatures.yaml")
+	content := `
+PatternSignatures:
+  - description: broken
+    match: '(abc'
+    part: partcontent
+    signatureid: broken
+    enable: 1
+    confidence-level: 3
+  - description: valid
+    match: 'abc'
+    part: partcontent
+    signatureid: valid
+    enable: 1
+    confidence-level: 3
+  - description: duplicate
+    match: 'def'
+    part: partcontent
+    signatureid: valid
+    enable: 1
+    confidence-level: 3
+  - description: optional
+    match: '(password)?'
+    part: partcontent
+    signatureid: optional
+    enable: 1
+    confidence-level: 3
+`
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+
+	sigs, _, err := Load([]string{file}, 3, Selection{})
+	assert.NoError(t, err)
+	// along with the built-in detectors
+	assert.Len(t, sigs, 2+len(builtinSignatures))
+	assert.Equal(t, "valid", sigs[0].SignatureID())
+	assert.Equal(t, "valid", sigs[0].Description())
+	// lint errors which used to load are only warnings
+	assert.Equal(t, "optional", sigs[1].SignatureID())
+}
@@ -2,7 +2,6 @@ package signatures
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -17,7 +16,10 @@ import (
 
 // PatternSignature holds the information about a pattern signature which is a regex used to match content within a file
 type PatternSignature struct {
-	match *regexp.Regexp
+	match  *regexp.Regexp
+	key    *regexp.Regexp // matched against the key of partkeyvalue signatures, any key if nil
+	filter secretFilter
+	secret int // index of the secret capture group, -1 if the whole match is the secret
 	GenericSignature
 }
 
@@ -31,23 +33,25 @@ func (s PatternSignature) ExtractMatch(file matchfile.MatchFile, change *object.
 	case PartExtension:
 		return s.match.MatchString(file.Extension), nil
 	case PartContent:
-		return s.partContent(file.Path, change, scanType)
+		return s.partContent(file, change, scanType)
+	case PartKeyValue:
+		// the pairs are parsed once per file and matched by Discover, see matchPairs
+		return false, nil
 	default: // TODO We need to do something with this
 		return false, nil
 	}
 }
 
-func (s PatternSignature) partContent(haystack string, change *object.Change, scanType api.ScanType) (bool, map[string]int) {
-	var contextMatches []string
+func (s PatternSignature) partContent(file matchfile.MatchFile, change *object.Change, scanType api.ScanType) (bool, map[string]int) {
 	results := make(map[string]int) // the secret and the line number in a map
 
-	if !util.PathExists(haystack) {
+	if file.Content == nil && !util.PathExists(file.Path) {
 		return false, nil
 	}
 
-	data, err := os.ReadFile(haystack)
+	data, err := file.Read()
 	if err != nil {
-		sErrAppend := fmt.Sprintf("ERROR --- Unable to open file for scanning: %q; Reason: %q", haystack, err)
+		sErrAppend := fmt.Sprintf("ERROR --- Unable to open file for scanning: %q; Reason: %q", file.Path, err)
 		results[sErrAppend] = 1 // set to zero due to error, we never have a line 0 so we can always ignore that or error on it
 		return false, results
 	}
@@ -56,15 +60,13 @@ func (s PatternSignature) partContent(haystack string, change *object.Change, sc
 	// will get a slice of all the individual matches. Doing this ahead of time saves us
 	// from looping through if it is not necessary.
 	if s.match.Match(data) {
-		for _, curRegexMatch := range s.match.FindAll(data, -1) {
-			contextMatches = append(contextMatches, string(curRegexMatch))
-		}
-		if len(contextMatches) > 0 {
-			return examineMatchResults(contextMatches, false, s.entropy, string(data))
+		if contextMatches := s.findAll(data); len(contextMatches) > 0 {
+			return examineMatchResults(contextMatches, false, s.filter, string(data))
 		}
 	}
 
-	if scanType == api.LocalPath {
+	// the entries of archives are only read from the archive
+	if scanType == api.LocalPath || file.Content != nil {
 		return false, results
 	}
 
@@ -74,26 +76,59 @@ func (s PatternSignature) partContent(haystack string, change *object.Change, sc
 	}
 
 	if s.match.Match([]byte(content)) {
-		for _, curRegexMatch := range s.match.FindAll([]byte(content), -1) {
-			contextMatches = append(contextMatches, string(curRegexMatch))
-		}
-		if len(contextMatches) > 0 {
-			return examineMatchResults(contextMatches, true, s.entropy, content)
+		if contextMatches := s.findAll([]byte(content)); len(contextMatches) > 0 {
+			return examineMatchResults(contextMatches, true, s.filter, content)
 		}
 	}
 
 	return false, nil
 }
 
-func examineMatchResults(contextMatches []string, dynamicMatch bool, entropy float64, content string) (bool, map[string]int) {
+// patternMatch holds a single match of a pattern and the secret within it
+type patternMatch struct {
+	match  string
+	secret string
+}
+
+func (s PatternSignature) findAll(data []byte) []patternMatch {
+	var res []patternMatch
+	for _, m := range s.match.FindAllSubmatch(data, -1) {
+		pm := patternMatch{match: string(m[0]), secret: string(m[0])}
+		if s.secret > 0 && m[s.secret] != nil {
+			pm.secret = string(m[s.secret])
+		}
+		res = append(res, pm)
+	}
+	return res
+}
+
+// Secret returns the secret within a match of the signature
+func (s PatternSignature) Secret(match string) string {
+	if s.secret > 0 {
+		if m := s.match.FindStringSubmatch(match); m != nil && m[s.secret] != "" {
+			return m[s.secret]
+		}
+	}
+	return match
+}
+
+// secretOf returns the secret within a match of the signature, the whole match for signatures without a secret group
+func secretOf(sig Signature, match string) string {
+	if ps, ok := sig.(PatternSignature); ok {
+		return ps.Secret(match)
+	}
+	return match
+}
+
+func examineMatchResults(contextMatches []patternMatch, dynamicMatch bool, filter secretFilter, content string) (bool, map[string]int) {
 	bResult := true
 	var results = make(map[string]int)
 	var mn int
 	for i, curMatch := range contextMatches {
-		thisMatch := string(curMatch[:])
-		thisMatch = strings.TrimSuffix(thisMatch, "\n")
+		thisMatch := strings.TrimSuffix(curMatch.match, "\n")
+		secret := strings.TrimSuffix(curMatch.secret, "\n")
 
-		bResult = confirmEntropy(thisMatch, entropy)
+		bResult = filter.accept(secret)
 
 		if bResult {
 			mn = 0
@@ -0,0 +1,147 @@
+package signatures
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
+	"github.com/rumenvasilev/rvsecret/internal/core/privatekey"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// BuiltinSource is the source of the signatures that are part of rvsecret rather than a signature file
+const BuiltinSource = "builtin"
+
+// PrivateKeySignatureID is the id of the built-in private key detector
+const PrivateKeySignatureID = "private-key"
+
+// builtinSignatures are the definitions of the detectors implemented in code. They're enabled, disabled and
+// filtered like the signatures of the signature files.
+var builtinSignatures = []SignatureDef{
+	{
+		SignatureID:     PrivateKeySignatureID,
+		Description:     "Private key",
+		Comment:         "PEM, OpenSSH, PuTTY and PKCS#12 private keys, validated by parsing them",
+		Part:            "partcontent",
+		Severity:        SeverityCritical,
+		Category:        "private-key",
+		Tags:            []string{"key"},
+		Source:          BuiltinSource,
+		Enable:          1,
+		ConfidenceLevel: 5,
+	},
+}
+
+// KeySignature is the built-in detector of private keys. Unlike pattern signatures a key spanning several
+// lines is reported once, and its fingerprint identifies it wherever it leaked.
+type KeySignature struct {
+	GenericSignature
+}
+
+// ExtractMatch is a placeholder, the keys are found by Discover along with the other content of the file
+func (s KeySignature) ExtractMatch(file matchfile.MatchFile, change *object.Change, scanType api.ScanType) (bool, map[string]int) {
+	return false, nil
+}
+
+// Enable sets whether as signature is active or not
+func (s KeySignature) Enable() int {
+	return s.enable
+}
+
+// ConfidenceLevel sets the confidence level of the pattern
+func (s KeySignature) ConfidenceLevel() int {
+	return s.confidenceLevel
+}
+
+// Part sets the part of the file/path that is matched [ filename content extension ]
+func (s KeySignature) Part() string {
+	return s.part
+}
+
+// Description sets the user comment of the signature
+func (s KeySignature) Description() string {
+	return s.description
+}
+
+// SignatureID sets the id used to identify the signature
+func (s KeySignature) SignatureID() string {
+	return s.signatureid
+}
+
+// keystoreSignatures returns the signatures run over binary keystores, whose content is only read by the
+// private key detector. The signatures of their name and path are kept.
+func keystoreSignatures(sigs []Signature) []Signature {
+	var res []Signature
+	for _, sig := range sigs {
+		if _, ok := sig.(KeySignature); ok || (sig.Part() != PartContent && sig.Part() != PartKeyValue) {
+			res = append(res, sig)
+		}
+	}
+	return res
+}
+
+// foundKey is a private key of the file, possibly decoded from an encoded blob or a value of a structured file
+type foundKey struct {
+	encoding string
+	encoded  string
+	privatekey.Key
+}
+
+// findKeys will look for keys in the file, its decoded blobs and the values of its key/value pairs, e.g. the
+// escaped keys of JSON service account files. A key found several ways, i.e. on overlapping lines, is only
+// reported once.
+func findKeys(data []byte, segments []decodedSegment, pairs []keyValue) []foundKey {
+	var res []foundKey
+	add := func(k foundKey) {
+		for _, v := range res {
+			if v.ID() == k.ID() && v.StartLine <= k.EndLine && k.StartLine <= v.EndLine {
+				return
+			}
+		}
+		res = append(res, k)
+	}
+
+	for _, k := range privatekey.Find(data) {
+		add(foundKey{Key: k})
+	}
+	for _, seg := range segments {
+		for _, k := range privatekey.Find([]byte(seg.text)) {
+			k.StartLine, k.EndLine = seg.line, seg.line
+			add(foundKey{encoding: seg.encoding, encoded: seg.encoded, Key: k})
+		}
+	}
+	for _, p := range pairs {
+		for _, k := range privatekey.Find([]byte(p.Value)) {
+			k.EndLine = p.Line + k.EndLine - k.StartLine
+			k.StartLine = p.Line
+			add(foundKey{encoding: p.encoding, encoded: p.encoded, Key: k})
+		}
+	}
+	return res
+}
+
+// discoverKeys will report the private keys of the file found by the built-in detector
+func discoverKeys(sig Signature, keys []foundKey, hideSecrets bool) []DiscoverOutput {
+	if _, ok := sig.(KeySignature); !ok {
+		return nil
+	}
+	var res []DiscoverOutput
+	for _, k := range keys {
+		out := DiscoverOutput{
+			Sig:         sig,
+			Secret:      k.Text,
+			Encoding:    k.encoding,
+			LineNum:     k.StartLine,
+			EndLineNum:  k.EndLine,
+			Fingerprint: k.Fingerprint,
+			KeyInfo:     k.String(),
+		}
+		if k.Encrypted {
+			out.Severity = SeverityHigh
+		}
+		if !hideSecrets {
+			out.Content = k.Text
+			out.Encoded = k.encoded
+		}
+		res = append(res, out)
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+package signatures
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/core/privatekey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestDiscoverKeys(t *testing.T) {
+	sig, err := newSignature(builtinSignatures[0], builtinKind)
+	require.NoError(t, err)
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(key, "deploy")
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(key.Public())
+	require.NoError(t, err)
+	fingerprint := ssh.FingerprintSHA256(sshPub)
+	text := strings.TrimSuffix(string(pem.EncodeToMemory(block)), "\n")
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []DiscoverOutput
+	}{
+		{"dotenv", ".env", "APP=web\nDEPLOY_KEY=\"" + text + "\"\n",
+			[]DiscoverOutput{{Sig: sig, Content: text, Secret: text, Fingerprint: fingerprint, KeyInfo: "ed25519 openssh", LineNum: 2, EndLineNum: 9}}},
+		{"json", "sa.json", `{"type": "service_account",` + "\n" + `"private_key": "` + strings.ReplaceAll(text, "\n", `\n`) + `"}`,
+			[]DiscoverOutput{{Sig: sig, Content: strings.ReplaceAll(text, "\n", `\n`), Secret: strings.ReplaceAll(text, "\n", `\n`), Fingerprint: fingerprint, KeyInfo: "ed25519 openssh", LineNum: 2, EndLineNum: 2}}},
+		{"base64", "secret.yaml", "data:\n  id_ed25519: " + base64.StdEncoding.EncodeToString([]byte(text)) + "\n",
+			[]DiscoverOutput{{Sig: sig, Content: text, Secret: text, Encoding: EncodingBase64, Encoded: base64.StdEncoding.EncodeToString([]byte(text)), Fingerprint: fingerprint, KeyInfo: "ed25519 openssh", LineNum: 2, EndLineNum: 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := findKeys([]byte(tt.content), decodeContent(tt.content, 2), parsePairs(tt.file, tt.content, 2))
+			assert.Equal(t, tt.want, discoverKeys(sig, keys, false))
+		})
+	}
+}
+
+func TestDiscoverKeys_encrypted(t *testing.T) {
+	sig, err := newSignature(builtinSignatures[0], builtinKind)
+	require.NoError(t, err)
+	keys := []foundKey{{Key: privatekey.Key{Format: privatekey.FormatPKCS12, StartLine: 1, EndLine: 1, Encrypted: true}}}
+
+	assert.Equal(t, []DiscoverOutput{
+		{Sig: sig, KeyInfo: "pkcs12, encrypted", Severity: SeverityHigh, LineNum: 1, EndLineNum: 1},
+	}, discoverKeys(sig, keys, true))
+}
+
+func TestKeystoreSignatures(t *testing.T) {
+	key, err := newSignature(builtinSignatures[0], builtinKind)
+	require.NoError(t, err)
+	content, err := newSignature(SignatureDef{SignatureID: "content", Match: "password", Part: "partcontent"}, patternKind)
+	require.NoError(t, err)
+	pairs, err := newSignature(SignatureDef{SignatureID: "pairs", Match: ".+", Key: "password", Part: "partkeyvalue"}, patternKind)
+	require.NoError(t, err)
+	extension, err := newSignature(SignatureDef{SignatureID: "extension", Match: ".p12", Part: "partextension"}, simpleKind)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Signature{key, extension}, keystoreSignatures([]Signature{content, key, pairs, extension}))
+}
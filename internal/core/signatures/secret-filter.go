@@ -0,0 +1,170 @@
+package signatures
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// Character sets used to compute the entropy of a secret
+const (
+	CharsetHex          = "hex"
+	CharsetBase64       = "base64"
+	CharsetAlphanumeric = "alphanumeric"
+)
+
+// secretGroup is the name of the capture group holding the secret within a pattern match. When the
+// pattern doesn't define one, the whole match is treated as the secret.
+const secretGroup = "secret"
+
+// charsets holds the characters of every supported charset and the entropy threshold used when the
+// signature doesn't set one. The thresholds are close to the maximum entropy of a 20 character random
+// string of each charset, as most tokens are at least that long.
+var charsets = map[string]struct {
+	chars   string
+	entropy float64
+}{
+	CharsetHex:          {"0123456789abcdefABCDEF", 3.0},
+	CharsetBase64:       {"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=_-", 4.2},
+	CharsetAlphanumeric: {"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", 3.7},
+}
+
+var (
+	// placeholderPatterns match values that are references to secrets or obvious examples, rather than secrets
+	placeholderPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\$\{[^}]*\}`),                  // ${VAR}
+		regexp.MustCompile(`^\$[A-Za-z_][A-Za-z0-9_]*$`),   // $VAR
+		regexp.MustCompile(`^%[A-Za-z_][A-Za-z0-9_]*%$`),   // %VAR%
+		regexp.MustCompile(`\{\{[^}]*\}\}`),                // {{ .Values.password }}
+		regexp.MustCompile(`<%[^%]*%>`),                    // <%= ENV['TOKEN'] %>
+		regexp.MustCompile(`#\{[^}]*\}`),                   // #{token}
+		regexp.MustCompile(`%\([A-Za-z_][A-Za-z0-9_]*\)s`), // %(password)s
+		regexp.MustCompile(`^<[^<>]+>$`),                   // <your-token>
+		regexp.MustCompile(`(?i)x{4,}|\*{4,}|#{4,}|\.{4,}`),
+		regexp.MustCompile(`(?i)change[_-]?me|replace[_-]?me|placeholder|dummy|redacted|insert[_-]?\w*[_-]?here|your[_-]?\w*[_-]?(key|token|secret|password)`),
+	}
+	// placeholderValues are rejected only when they are the whole secret, as they commonly appear in real ones
+	placeholderValues = []string{"password", "passwd", "secret", "token", "example", "sample", "test", "null", "nil", "none", "true", "false", "undefined"}
+)
+
+// secretFilter holds the rule-level checks applied to every match of a pattern signature
+type secretFilter struct {
+	charset            string
+	stopwords          []string
+	entropy            float64
+	minLength          int
+	maxLength          int
+	rejectPlaceholders bool
+}
+
+func newSecretFilter(def SignatureDef) secretFilter {
+	return secretFilter{
+		charset:            strings.ToLower(def.EntropyCharset),
+		stopwords:          def.Stopwords,
+		entropy:            def.Entropy,
+		minLength:          def.MinLength,
+		maxLength:          def.MaxLength,
+		rejectPlaceholders: def.RejectPlaceholders,
+	}
+}
+
+// accept will return true if the secret passes the length, placeholder and entropy checks
+func (f secretFilter) accept(secret string) bool {
+	if f.minLength > 0 && len(secret) < f.minLength {
+		return false
+	}
+	if f.maxLength > 0 && len(secret) > f.maxLength {
+		return false
+	}
+	if f.rejectPlaceholders && isPlaceholder(secret) {
+		return false
+	}
+	if containsStopword(secret, f.stopwords) {
+		return false
+	}
+	if f.charset == "" {
+		return confirmEntropy(secret, f.entropy)
+	}
+	return confirmCharsetEntropy(secret, f.charset, f.entropy)
+}
+
+// confirmCharsetEntropy will compute the entropy of the longest run of characters from the charset within
+// the secret, so that delimiters and prefixes don't skew the result. A zero threshold uses the charset default.
+func confirmCharsetEntropy(secret, charset string, threshold float64) bool {
+	cs, ok := charsets[charset]
+	if !ok {
+		return false
+	}
+	if threshold == 0 {
+		threshold = cs.entropy
+	}
+	run := longestRun(secret, cs.chars)
+	if run == "" || util.GetEntropyInt(run) < threshold {
+		return false
+	}
+	return !IsSafeText(&secret)
+}
+
+func longestRun(s, chars string) string {
+	var longest string
+	start := -1
+	for i, r := range s + "\x00" {
+		if r != 0 && strings.ContainsRune(chars, r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && i-start > len(longest) {
+			longest = s[start:i]
+		}
+		start = -1
+	}
+	return longest
+}
+
+// isPlaceholder will return true if the secret looks like a template expression, a reference to a variable,
+// an obvious example value or a repetition of a few characters
+func isPlaceholder(secret string) bool {
+	s := strings.Trim(strings.TrimSpace(secret), `"'`+"`")
+	if s == "" {
+		return true
+	}
+	for _, v := range placeholderValues {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	for _, re := range placeholderPatterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return isRepeated(s)
+}
+
+// isRepeated will return true if s is made of a sequence of up to 4 characters repeated over and over,
+// e.g. aaaaaaaa, abababab or 12341234
+func isRepeated(s string) bool {
+	maxPeriod := len(s) / 2
+	if maxPeriod > 4 {
+		maxPeriod = 4
+	}
+	for p := 1; p <= maxPeriod; p++ {
+		if strings.Repeat(s[:p], len(s)/p+1)[:len(s)] == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStopword(secret string, stopwords []string) bool {
+	s := strings.ToLower(secret)
+	for _, v := range stopwords {
+		if v != "" && strings.Contains(s, strings.ToLower(v)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package signatures
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Selection holds the signatures explicitly enabled or disabled by the user, on top of the enable flag
+// and the confidence level from the signature files. Every entry is either a signature id or a glob
+// pattern ('*' and '?' wildcards) matched case-insensitively against the id, the description, the
+// category and the tags. A signature matched by both lists is disabled.
+// Severity and Tags narrow down the remaining signatures to the ones with at least the given severity
+// and at least one of the given tags or category.
+type Selection struct {
+	Severity string
+	Enable   []string
+	Disable  []string
+	Tags     []string
+}
+
+type selectionState int
+
+const (
+	notSelected selectionState = iota
+	selectedEnabled
+	selectedDisabled
+)
+
+func (s Selection) state(def SignatureDef) selectionState {
+	switch {
+	case matchesAny(s.Disable, def):
+		return selectedDisabled
+	case matchesAny(s.Enable, def):
+		return selectedEnabled
+	default:
+		return notSelected
+	}
+}
+
+func matchesAny(patterns []string, def SignatureDef) bool {
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if strings.EqualFold(p, def.SignatureID) {
+			return true
+		}
+		re := globToRegexp(p)
+		if re.MatchString(def.SignatureID) || re.MatchString(def.Description) {
+			return true
+		}
+		if HasAnyTag(def.Tags, def.Category, []string{p}) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp will convert a glob pattern into an anchored, case-insensitive regular expression.
+// Unlike path.Match the wildcards match any character, as descriptions commonly contain slashes.
+func globToRegexp(glob string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?i)^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+// status will decide whether the signature is used during a scan. Invalid signatures are reported
+// first as they can't be used regardless of the options.
+func status(def SignatureDef, kind signatureKind, mLevel int, sel Selection) (string, []LintIssue) {
+	issues := loadIssues(def, kind)
+	if HasErrors(issues) {
+		return StatusInvalid, issues
+	}
+	return selected(def, mLevel, sel), issues
+}
+
+// selected will decide whether the signature is enabled based on the user selection, followed by
+// its enable flag, confidence level, severity and tags
+func selected(def SignatureDef, mLevel int, sel Selection) string {
+	switch sel.state(def) {
+	case selectedDisabled:
+		return StatusDisabled
+	case selectedEnabled:
+		return StatusEnabled
+	}
+	switch {
+	case def.Enable <= 0:
+		return StatusDisabled
+	case def.ConfidenceLevel < mLevel:
+		return StatusBelowLevel
+	case !SeverityAtLeast(getSeverity(def), sel.Severity):
+		return StatusFiltered
+	case len(sel.Tags) > 0 && !HasAnyTag(def.Tags, def.Category, sel.Tags):
+		return StatusFiltered
+	default:
+		return StatusEnabled
+	}
+}
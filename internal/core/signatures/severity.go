@@ -0,0 +1,77 @@
+package signatures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity levels describe the impact of a leaked secret, in increasing order
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// DefaultSeverity is used for signatures that don't declare one
+const DefaultSeverity = SeverityMedium
+
+var severities = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// ParseSeverity will validate the severity level and return it in its canonical form. An empty value
+// is returned as is, meaning that no severity was requested.
+func ParseSeverity(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || severityRank(s) >= 0 {
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown severity %q, must be one of %s", s, strings.Join(severities, ", "))
+}
+
+// SeverityAtLeast returns true if the severity is the same or higher than the threshold. An empty threshold
+// is always met.
+func SeverityAtLeast(severity, threshold string) bool {
+	if threshold == "" {
+		return true
+	}
+	return severityRank(strings.ToLower(severity)) >= severityRank(strings.ToLower(threshold))
+}
+
+func severityRank(s string) int {
+	for i, v := range severities {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// getSeverity will return the severity of the signature definition, or the default one if not set
+func getSeverity(sigDef SignatureDef) string {
+	s := strings.ToLower(strings.TrimSpace(sigDef.Severity))
+	if s == "" {
+		return DefaultSeverity
+	}
+	return s
+}
+
+// HasAnyTag returns true if any of the tags or the category matches one of the given glob patterns
+func HasAnyTag(tags []string, category string, patterns []string) bool {
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		re := globToRegexp(p)
+		if category != "" && re.MatchString(category) {
+			return true
+		}
+		for _, t := range tags {
+			if re.MatchString(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
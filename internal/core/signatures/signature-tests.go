@@ -0,0 +1,134 @@
+package signatures
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/core/keyvalue"
+	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// TestResult holds the outcome of running a single signature example
+type TestResult struct {
+	SignatureID string
+	Description string
+	Example     string
+	Err         error
+	WantMatch   bool
+	Passed      bool
+}
+
+// TestReport holds the results of running the examples of a signature set
+type TestReport struct {
+	Results  []TestResult
+	Untested int // signatures without any examples
+}
+
+// Failed returns the number of examples that didn't behave as expected
+func (r TestReport) Failed() int {
+	var cnt int
+	for _, v := range r.Results {
+		if !v.Passed {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// TestFile will load the signatures file and run the examples embedded in every signature
+func TestFile(filePath string) (TestReport, error) {
+	fp, err := util.SetHomeDir(strings.TrimSpace(filePath))
+	if err != nil {
+		return TestReport{}, err
+	}
+	c, err := loadSignatureSet(fp)
+	if err != nil {
+		return TestReport{}, fmt.Errorf("failed to load signatures file %s: %w", filePath, err)
+	}
+	return RunTests(c)
+}
+
+// RunTests will compile every signature in the set, regardless of whether it is enabled, and run its
+// examples through the same matching and entropy checks that are used during a scan.
+func RunTests(c SignatureConfig) (TestReport, error) {
+	var report TestReport
+	// content examples have to be matched against real files
+	dir, err := os.MkdirTemp("", "rvsecret-tests")
+	if err != nil {
+		return report, err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	for _, set := range c.sets() {
+		for _, def := range set.defs {
+			if len(def.Tests.Match) == 0 && len(def.Tests.NoMatch) == 0 {
+				report.Untested++
+			}
+			report.Results = append(report.Results, testSignature(def, set.kind, dir)...)
+		}
+	}
+	return report, nil
+}
+
+func testSignature(def SignatureDef, kind signatureKind, dir string) []TestResult {
+	tpl := TestResult{SignatureID: def.SignatureID, Description: def.Description}
+	sig, err := newSignature(def, kind)
+	if err != nil {
+		tpl.Err = err
+		return []TestResult{tpl}
+	}
+
+	var results []TestResult
+	examples := []struct {
+		values    []string
+		wantMatch bool
+	}{
+		{def.Tests.Match, true},
+		{def.Tests.NoMatch, false},
+	}
+	for _, e := range examples {
+		for _, v := range e.values {
+			res := tpl
+			res.Example = v
+			res.WantMatch = e.wantMatch
+			var ok bool
+			ok, res.Err = runExample(sig, v, dir)
+			res.Passed = res.Err == nil && ok == e.wantMatch
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// runExample will match a single example with the signature. Examples for content signatures
+// are written to a temporary file, examples for key/value signatures are read as `key: value`
+// properties, all the others are treated as the path to a file.
+func runExample(sig Signature, example, dir string) (bool, error) {
+	if s, ok := sig.(SafeFunctionSignature); ok {
+		return s.match.MatchString(example), nil
+	}
+	if s, ok := sig.(PatternSignature); ok && s.part == PartKeyValue {
+		pairs, err := keyvalue.ParseFormat(keyvalue.FormatProperties, []byte(example))
+		return len(s.matchPairs(withDecodedPairs(pairs, 0))) > 0, err
+	}
+
+	mf := matchfile.New(example)
+	if sig.Part() == PartContent {
+		f, err := os.CreateTemp(dir, "example-*")
+		if err != nil {
+			return false, err
+		}
+		_, err = f.WriteString(example)
+		f.Close() //nolint:errcheck
+		if err != nil {
+			return false, err
+		}
+		mf = matchfile.New(f.Name())
+	}
+
+	ok, _ := sig.ExtractMatch(mf, nil, api.LocalPath)
+	return ok, nil
+}
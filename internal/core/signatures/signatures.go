@@ -3,17 +3,19 @@ package signatures
 import (
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
+	_git "github.com/rumenvasilev/rvsecret/internal/core/git"
+	"github.com/rumenvasilev/rvsecret/internal/core/keyvalue"
 	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
+	"github.com/rumenvasilev/rvsecret/internal/core/privatekey"
+	"github.com/rumenvasilev/rvsecret/internal/core/verify"
 	"github.com/rumenvasilev/rvsecret/internal/log"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
 	"github.com/rumenvasilev/rvsecret/internal/util"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
-	"gopkg.in/yaml.v2"
 )
 
 // These are the various items that we are attempting to match against using either regex's or simple pattern matches.
@@ -22,6 +24,7 @@ const (
 	PartFilename  = "filename"  // file name
 	PartPath      = "path"      // the path to the file
 	PartContent   = "content"   // the content of the file
+	PartKeyValue  = "keyvalue"  // the key/value pairs of structured files, e.g. YAML or .env
 )
 
 type signatureKind int
@@ -31,37 +34,65 @@ const (
 	simpleKind
 	patternKind
 	safeFunctionKind
+	builtinKind
 )
 
 type GenericSignature struct {
+	category        string
 	comment         string
 	description     string
 	part            string
+	severity        string
 	signatureid     string
+	source          string
+	tags            []string
+	verifier        *verify.Definition
 	enable          int
 	entropy         float64
 	confidenceLevel int
 }
 
+// Source returns the file the signature was loaded from
+func (g GenericSignature) Source() string {
+	return g.source
+}
+
+// Severity returns the impact of a secret matched by the signature
+func (g GenericSignature) Severity() string {
+	return g.severity
+}
+
+// Category returns the kind of secret matched by the signature, e.g. cloud or private-key
+func (g GenericSignature) Category() string {
+	return g.category
+}
+
+// Tags returns the free-form labels of the signature
+func (g GenericSignature) Tags() []string {
+	return g.tags
+}
+
+// Verifier returns the definition of the check whether a matched secret is live, nil if the signature has none
+func (g GenericSignature) Verifier() *verify.Definition {
+	return g.verifier
+}
+
 // SafeFunctionSignatures is a collection of safe function sigs
 var SafeFunctionSignatures []SafeFunctionSignature
 
 // loadSignatureSet will read in the defined signatures from an external source
 func loadSignatureSet(filename string) (SignatureConfig, error) {
-	if !util.PathExists(filename) {
-		return SignatureConfig{}, errors.New("Couldn't find the path to signatures file")
-	}
-	bytes, err := os.ReadFile(filename)
+	c, warnings, err := Convert(filename)
 	if err != nil {
 		return SignatureConfig{}, err
 	}
-
-	var c SignatureConfig
-	err = yaml.Unmarshal(bytes, &c)
-	if err != nil {
-		return SignatureConfig{}, err
+	if len(warnings) > 0 {
+		log.Log.Warn("%s: %d rule %s couldn't be imported as is, run `rvsecret signatures convert %s` for details",
+			filename, len(warnings), util.Pluralize(len(warnings), "option", "options"), filename)
+		for _, v := range warnings {
+			log.Log.Debug("%s: %s", filename, v.String())
+		}
 	}
-
 	return c, nil
 }
 
@@ -73,6 +104,11 @@ type Signature interface {
 	ConfidenceLevel() int
 	Part() string
 	SignatureID() string // TODO change id -> ID
+	Source() string
+	Severity() string
+	Category() string
+	Tags() []string
+	Verifier() *verify.Definition
 }
 
 // SignaturesMetaData is used by updateSignatures to determine if/how to update the signatures
@@ -84,14 +120,41 @@ type SignaturesMetaData struct {
 
 // SignatureDef maps to a signature within the yaml file
 type SignatureDef struct {
-	Comment         string  `yaml:"comment"`
-	Description     string  `yaml:"description"`
-	Match           string  `yaml:"match"`
-	Part            string  `yaml:"part"`
-	SignatureID     string  `yaml:"signatureid"`
-	Enable          int     `yaml:"enable"`
-	Entropy         float64 `yaml:"entropy"`
-	ConfidenceLevel int     `yaml:"confidence-level"`
+	Category        string         `yaml:"category,omitempty"`
+	Comment         string         `yaml:"comment"`
+	Description     string         `yaml:"description"`
+	Match           string         `yaml:"match"`
+	Part            string         `yaml:"part"`
+	Severity        string         `yaml:"severity,omitempty"`
+	SignatureID     string         `yaml:"signatureid"`
+	Source          string         `yaml:"-"` // the file the signature was loaded from
+	Tags            []string       `yaml:"tags,omitempty"`
+	Tests           SignatureTests `yaml:"tests,omitempty"`
+	Enable          int            `yaml:"enable"`
+	Entropy         float64        `yaml:"entropy"`
+	ConfidenceLevel int            `yaml:"confidence-level"`
+
+	// The following options only apply to pattern signatures. They are checked against the "secret"
+	// named capture group of the match, or the whole match if the pattern doesn't define one.
+	EntropyCharset     string   `yaml:"entropy-charset,omitempty"` // hex, base64 or alphanumeric
+	Stopwords          []string `yaml:"stopwords,omitempty"`       // matches containing any of these are rejected
+	MinLength          int      `yaml:"min-length,omitempty"`
+	MaxLength          int      `yaml:"max-length,omitempty"`
+	RejectPlaceholders bool     `yaml:"reject-placeholders,omitempty"`
+
+	// Key is matched against the key of every pair of partkeyvalue signatures, while match is
+	// matched against the value. Nested keys are joined with dots, e.g. spring.datasource.password.
+	Key string `yaml:"key,omitempty"`
+
+	// Verifier checks whether the secret of a match is a live credential, when scanning with --verify
+	Verifier *verify.Definition `yaml:"verifier,omitempty"`
+}
+
+// SignatureTests holds the examples embedded in a signature definition. Every entry in Match
+// must be detected by the signature, while none of the entries in NoMatch should be.
+type SignatureTests struct {
+	Match   []string `yaml:"match,omitempty"`
+	NoMatch []string `yaml:"nomatch,omitempty"`
 }
 
 // SignatureConfig holds the base file structure for the signatures file
@@ -102,6 +165,20 @@ type SignatureConfig struct {
 	SafeFunctionSignatures []SignatureDef     `yaml:"SafeFunctionSignatures"`
 }
 
+type signatureSet struct {
+	defs []SignatureDef
+	kind signatureKind
+}
+
+// sets returns the signature definitions grouped by their kind, in a stable order
+func (c SignatureConfig) sets() []signatureSet {
+	return []signatureSet{
+		{c.SimpleSignatures, simpleKind},
+		{c.PatternSignatures, patternKind},
+		{c.SafeFunctionSignatures, safeFunctionKind},
+	}
+}
+
 // IsSafeText check against known "safe" (aka not a password) list
 func IsSafeText(sMatchString *string) bool {
 	bResult := false
@@ -147,46 +224,44 @@ func fetchLineNumber(input *[]string, thisMatch string, idx int) int {
 	return lineNumIndexMap[idx]
 }
 
-// Load will load all known signatures for the various match types into the session
+// Load will load all known signatures for the various match types into the session. The signature files
+// are merged in the given order, see Sources for resolving them from the configuration. Signatures are
+// enabled based on their enable flag and confidence level, unless overridden by the selection.
 // Returns a slice of loaded signatures, signatures bundle version and an error
-func Load(filePath string, mLevel int) ([]Signature, string, error) {
-	f := strings.TrimSpace(filePath)
-	// ensure that we have the proper home directory
-	fp, err := util.SetHomeDir(f)
+func Load(files []string, mLevel int, sel Selection) ([]Signature, string, error) {
+	c, err := loadSignatureSets(files)
 	if err != nil {
 		return []Signature{}, "", err
 	}
-
-	c, err := loadSignatureSet(fp)
-	if err != nil {
-		return []Signature{}, "", fmt.Errorf("failed to load signatures file %s: %w", filePath, err)
-	}
+	// every file under the signatures path is loaded, so tell which ones they were
+	log.Log.Info("Loaded signatures from %s", strings.Join(files, ", "))
 	signaturesVersion := c.Meta.Version
-	// signaturesMetaData := SignaturesMetaData{
-	// 	Version: c.Meta.Version,
-	// 	Date:    c.Meta.Date,
-	// 	Time:    c.Meta.Time,
-	// }
 
-	// sess.SignatureVersion = signaturesMetaData.Version
-
-	simple := iter(c.SimpleSignatures, mLevel, simpleKind)
-	pattern := iter(c.PatternSignatures, mLevel, patternKind)
+	simple := iter(c.SimpleSignatures, mLevel, sel, simpleKind)
+	pattern := iter(c.PatternSignatures, mLevel, sel, patternKind)
 	// TODO are we loading the safe ones somewhere?
-	// safefunc, sfCnt := iter(c.SafeFunctionSignatures, mLevel, safeFunctionKind)
+	// safefunc, sfCnt := iter(c.SafeFunctionSignatures, mLevel, sel, safeFunctionKind)
 	all, cnt := mergeSignatures(simple, pattern)
+	builtin := iter(builtinSignatures, mLevel, sel, builtinKind)
+	all = append(all, builtin...)
+	cnt += len(builtin)
+	if log.Log.IsDebug() {
+		// validates every signature once more, only to describe them
+		_, stats := inspect(c, mLevel, sel)
+		log.Log.Debug("Signatures: %s", stats.String())
+	}
 	if cnt == 0 {
 		return nil, signaturesVersion, errors.New("no signatures were loaded")
 	}
 	return all, signaturesVersion, nil
 }
 
-func iter(sigDefs []SignatureDef, mLevel int, kind signatureKind) []Signature {
+func iter(sigDefs []SignatureDef, mLevel int, sel Selection, kind signatureKind) []Signature {
 	res := []Signature{}
 	for _, curSig := range sigDefs {
-		t := buildSignatureType(curSig, mLevel, kind)
+		t := buildSignatureType(curSig, mLevel, sel, kind)
 		if t == nil {
-			// skip disabled signatures
+			// skip disabled and invalid signatures
 			continue
 		}
 		res = append(res, t)
@@ -194,36 +269,87 @@ func iter(sigDefs []SignatureDef, mLevel int, kind signatureKind) []Signature {
 	return res
 }
 
-func buildSignatureType(curSig SignatureDef, mLevel int, kind signatureKind) Signature {
-	if curSig.Enable > 0 && curSig.ConfidenceLevel >= mLevel {
-		g := GenericSignature{
-			comment:         curSig.Comment,
-			description:     curSig.Description,
-			part:            getPart(curSig),
-			signatureid:     curSig.SignatureID,
-			enable:          curSig.Enable,
-			entropy:         curSig.Entropy,
-			confidenceLevel: curSig.ConfidenceLevel,
-		}
-		switch kind {
-		case simpleKind:
-			return SimpleSignature{
-				match:            curSig.Match,
-				GenericSignature: g,
+func buildSignatureType(curSig SignatureDef, mLevel int, sel Selection, kind signatureKind) Signature {
+	if selected(curSig, mLevel, sel) == StatusEnabled {
+		issues := validate(curSig, kind)
+		for _, v := range issues {
+			if v.Level == LintWarning {
+				log.Log.Debug("Signature %s", v.String())
 			}
-		case patternKind:
-			return PatternSignature{
-				match:            regexp.MustCompile(curSig.Match),
-				GenericSignature: g,
+		}
+		if HasErrors(issues) {
+			for _, v := range issues {
+				if v.Level == LintError {
+					log.Log.Error("Skipping signature %s", v.String())
+				}
 			}
-		case safeFunctionKind:
+			return nil
+		}
+		for _, v := range validateStrict(curSig, kind) {
+			log.Log.Warn("Signature %s, run `rvsecret signatures lint` for details", v.String())
+		}
+		s, err := newSignature(curSig, kind)
+		if err != nil {
+			log.Log.Error("Skipping signature %q, %s", curSig.SignatureID, err.Error())
+			return nil
+		}
+		return s
+	}
+	return nil
+}
+
+// newSignature will compile the signature definition into the signature type matching its kind,
+// regardless of whether it is enabled or not.
+func newSignature(curSig SignatureDef, kind signatureKind) (Signature, error) {
+	g := GenericSignature{
+		category:        curSig.Category,
+		comment:         curSig.Comment,
+		description:     curSig.Description,
+		part:            getPart(curSig),
+		severity:        getSeverity(curSig),
+		signatureid:     curSig.SignatureID,
+		source:          curSig.Source,
+		tags:            curSig.Tags,
+		verifier:        curSig.Verifier,
+		enable:          curSig.Enable,
+		entropy:         curSig.Entropy,
+		confidenceLevel: curSig.ConfidenceLevel,
+	}
+	switch kind {
+	case simpleKind:
+		return SimpleSignature{
+			match:            curSig.Match,
+			GenericSignature: g,
+		}, nil
+	case patternKind, safeFunctionKind:
+		match, err := regexp.Compile(curSig.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q, %w", curSig.Match, err)
+		}
+		if kind == safeFunctionKind {
 			return SafeFunctionSignature{
-				match:            regexp.MustCompile(curSig.Match),
+				match:            match,
 				GenericSignature: g,
+			}, nil
+		}
+		var key *regexp.Regexp
+		if curSig.Key != "" {
+			if key, err = regexp.Compile(curSig.Key); err != nil {
+				return nil, fmt.Errorf("invalid key expression %q, %w", curSig.Key, err)
 			}
 		}
+		return PatternSignature{
+			match:            match,
+			key:              key,
+			secret:           match.SubexpIndex(secretGroup),
+			filter:           newSecretFilter(curSig),
+			GenericSignature: g,
+		}, nil
+	case builtinKind:
+		return KeySignature{GenericSignature: g}, nil
+	default:
+		return nil, fmt.Errorf("unknown signature kind %d", kind)
 	}
-	return nil
 }
 
 func getPart(sigDef SignatureDef) string {
@@ -236,6 +362,8 @@ func getPart(sigDef SignatureDef) string {
 		return PartExtension
 	case "partcontent":
 		return PartContent
+	case "partkeyvalue":
+		return PartKeyValue
 	default:
 		return PartContent
 	}
@@ -261,17 +389,47 @@ func mergeSignatures(simple, pattern []Signature) ([]Signature, int) {
 }
 
 type DiscoverOutput struct {
-	Sig     Signature
-	Content string
-	LineNum int
+	Sig         Signature
+	Content     string
+	Secret      string // the secret within the match, set regardless of hiding the content
+	Encoding    string // the encoding of the blob the match was decoded from, empty if it wasn't encoded
+	Encoded     string // the encoded blob, as found in the file
+	Fingerprint string // the fingerprint of the public key of private keys
+	KeyInfo     string // the type, format and encryption of private keys, e.g. "rsa openssh, encrypted"
+	Severity    string // overrides the severity of the signature, e.g. for encrypted keys
+	LineNum     int
+	EndLineNum  int // set for matches spanning several lines, e.g. private keys
+	Column      int // set for matches of key/value pairs, as reported by the parser
 }
 
 func Discover(mf matchfile.MatchFile, change *object.Change, cfg *config.Config, sigs []Signature) (dirtyFile bool, dirtyCommit bool, ignored int, results []DiscoverOutput) {
 	var content string
 	var errors = make(map[string]int)
+	// the file is read once, for all the signatures matching its content
+	mf = mf.Cached()
+	if privatekey.IsKeystore(mf.Path) {
+		sigs = keystoreSignatures(sigs)
+	}
+	segments, pairs, keys := preparse(mf, change, cfg, sigs)
 	// for each signature that is loaded scan the file as a whole and generate a map of
 	// the match and the line number the match was found on
 	for _, sig := range sigs {
+		if decoded := discoverDecoded(sig, segments, cfg.Global.HideSecrets); len(decoded) > 0 {
+			dirtyFile = true
+			dirtyCommit = true
+			results = append(results, decoded...)
+		}
+		if matched := discoverPairs(sig, pairs, cfg.Global.HideSecrets); len(matched) > 0 {
+			dirtyFile = true
+			dirtyCommit = true
+			results = append(results, matched...)
+		}
+		if found := discoverKeys(sig, keys, cfg.Global.HideSecrets); len(found) > 0 {
+			dirtyFile = true
+			dirtyCommit = true
+			results = append(results, found...)
+		}
+
 		ok, matchMap := sig.ExtractMatch(mf, change, cfg.Global.ScanType)
 		if !ok {
 			util.MergeMaps(matchMap, errors)
@@ -285,16 +443,16 @@ func Discover(mf matchfile.MatchFile, change *object.Change, cfg *config.Config,
 		// create a new finding. This will produce dupes as the file may exist
 		// in multiple commits.
 		for k, v := range matchMap {
+			match := strings.SplitAfterN(k, "_", 2)[1]
 			// Default to no content, only publish information if explicitly allowed to
 			content = ""
-			if matchMap != nil && !cfg.Global.HideSecrets {
+			if !cfg.Global.HideSecrets {
 				// This sets the content for the finding, in this case the actual secret
 				// is the content. This can be removed and hidden via a commandline flag.
-				cleanK := strings.SplitAfterN(k, "_", 2)
-				content = cleanK[1]
+				content = match
 			}
 
-			results = append(results, DiscoverOutput{Content: content, Sig: sig, LineNum: v})
+			results = append(results, DiscoverOutput{Content: content, Secret: secretOf(sig, match), Sig: sig, LineNum: v})
 		}
 	}
 	ignored = len(errors)
@@ -305,3 +463,67 @@ func Discover(mf matchfile.MatchFile, change *object.Change, cfg *config.Config,
 	}
 	return //dirtyFile, dirtyCommit, ignored, results
 }
+
+// preparse will read the content of the file once for the signatures matching decoded blobs, key/value
+// pairs and private keys, rather than by every signature on its own
+func preparse(mf matchfile.MatchFile, change *object.Change, cfg *config.Config, sigs []Signature) ([]decodedSegment, []keyValue, []foundKey) {
+	var hasContent, hasPairs, hasKeys bool
+	for _, sig := range sigs {
+		switch sig.(type) {
+		case PatternSignature:
+			hasContent = hasContent || sig.Part() == PartContent
+			hasPairs = hasPairs || sig.Part() == PartKeyValue
+		case KeySignature:
+			hasKeys = true
+		}
+	}
+	hasContent = (hasContent || hasKeys) && cfg.Global.DecodeDepth > 0
+	hasPairs = (hasPairs || hasKeys) && keyvalue.Format(mf.Path) != ""
+	if !hasContent && !hasPairs && !hasKeys {
+		return nil, nil, nil
+	}
+
+	data, err := mf.Read()
+	if err != nil && change != nil && cfg.Global.ScanType != api.LocalPath {
+		content, _ := _git.GetChangeContent(change)
+		data = []byte(content)
+	}
+	content := string(data)
+
+	var segments []decodedSegment
+	var pairs []keyValue
+	if hasContent {
+		segments = decodeContent(content, cfg.Global.DecodeDepth)
+	}
+	if hasPairs {
+		pairs = parsePairs(mf.Path, content, cfg.Global.DecodeDepth)
+	}
+	var keys []foundKey
+	if hasKeys {
+		keys = findKeys(data, segments, pairs)
+	}
+	return segments, pairs, keys
+}
+
+// discoverDecoded will match the signature against the decoded blobs of the file
+func discoverDecoded(sig Signature, segments []decodedSegment, hideSecrets bool) []DiscoverOutput {
+	ps, ok := sig.(PatternSignature)
+	if !ok || ps.part != PartContent || len(segments) == 0 {
+		return nil
+	}
+	var res []DiscoverOutput
+	for _, m := range ps.matchDecoded(segments) {
+		out := DiscoverOutput{
+			Sig:      sig,
+			Secret:   strings.TrimSuffix(m.secret, "\n"),
+			Encoding: m.segment.encoding,
+			LineNum:  m.segment.line,
+		}
+		if !hideSecrets {
+			out.Content = strings.TrimSuffix(m.match, "\n")
+			out.Encoded = m.segment.encoded
+		}
+		res = append(res, out)
+	}
+	return res
+}
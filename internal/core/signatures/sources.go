@@ -0,0 +1,164 @@
+package signatures
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// Sources will resolve the signatures path and files into an ordered list of signature files.
+// Files found under the path come first, sorted by name, followed by the files in the order
+// they were given. When signatures get merged, the ones coming later take precedence.
+func Sources(path string, files []string) ([]string, error) {
+	var res []string
+	add := func(f string) {
+		// a file given twice keeps its last (highest precedence) position
+		for i, v := range res {
+			if v == f {
+				res = append(res[:i], res[i+1:]...)
+				break
+			}
+		}
+		res = append(res, f)
+	}
+
+	if strings.TrimSpace(path) != "" {
+		dir, err := util.SetHomeDir(strings.TrimSpace(path))
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case util.PathExists(dir):
+			found, err := findSignatureFiles(dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range found {
+				add(f)
+			}
+		case len(files) == 0:
+			return nil, fmt.Errorf("couldn't find the signatures path %s", path)
+		default:
+			log.Log.Debug("Signatures path %s doesn't exist, skipping it", path)
+		}
+	}
+
+	for _, v := range files {
+		f, err := util.SetHomeDir(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		fi, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find the signatures file %s", v)
+		}
+		if !fi.IsDir() {
+			add(filepath.Clean(f))
+			continue
+		}
+		found, err := findSignatureFiles(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, ff := range found {
+			add(ff)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("no signature files were found")
+	}
+	return res, nil
+}
+
+// findSignatureFiles will walk the directory and return all the signature files in it, sorted by name
+func findSignatureFiles(dir string) ([]string, error) {
+	var res []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".yml", ".yaml", ".toml":
+			res = append(res, path)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// loadSignatureSets will read and merge all the signature files. Signatures sharing a signatureid
+// with one from a previous file replace it, duplicates within the same file are skipped.
+// The version of the first file that declares one is used for the merged set.
+func loadSignatureSets(files []string) (SignatureConfig, error) {
+	var sets []SignatureConfig
+	for _, f := range files {
+		c, err := loadSignatureSet(f)
+		if err != nil {
+			return SignatureConfig{}, fmt.Errorf("failed to load signatures file %s: %w", f, err)
+		}
+		sets = append(sets, c)
+	}
+	return mergeSignatureSets(sets...), nil
+}
+
+func mergeSignatureSets(sets ...SignatureConfig) SignatureConfig {
+	type entry struct {
+		def  SignatureDef
+		kind signatureKind
+	}
+	var entries []*entry
+	index := make(map[string]*entry)
+
+	var res SignatureConfig
+	for _, c := range sets {
+		if res.Meta.Version == "" {
+			res.Meta = c.Meta
+		} else if c.Meta.Version != "" && c.Meta.Version != res.Meta.Version {
+			log.Log.Debug("Ignoring signatures version %s, using %s", c.Meta.Version, res.Meta.Version)
+		}
+
+		for _, set := range c.sets() {
+			for _, def := range set.defs {
+				e := &entry{def: def, kind: set.kind}
+				if def.SignatureID == "" {
+					// will be reported as invalid when loaded
+					entries = append(entries, e)
+					continue
+				}
+				prev, ok := index[def.SignatureID]
+				switch {
+				case !ok:
+					index[def.SignatureID] = e
+					entries = append(entries, e)
+				case prev.def.Source == def.Source:
+					log.Log.Error("Skipping signature %q from %s, duplicate signatureid", def.SignatureID, def.Source)
+				default:
+					log.Log.Info("Signature %q from %s overrides the one from %s", def.SignatureID, def.Source, prev.def.Source)
+					*prev = *e
+				}
+			}
+		}
+	}
+
+	for _, e := range entries {
+		switch e.kind {
+		case simpleKind:
+			res.SimpleSignatures = append(res.SimpleSignatures, e.def)
+		case patternKind:
+			res.PatternSignatures = append(res.PatternSignatures, e.def)
+		case safeFunctionKind:
+			res.SafeFunctionSignatures = append(res.SafeFunctionSignatures, e.def)
+		}
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package signatures
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestSources(t *testing.T) {
+	dir := t.TempDir()
+	def := writeFile(t, filepath.Join(dir, "bundle", "default.yaml"), "")
+	nested := writeFile(t, filepath.Join(dir, "bundle", "extra", "cloud.yml"), "")
+	writeFile(t, filepath.Join(dir, "bundle", "README.md"), "")
+	private := writeFile(t, filepath.Join(dir, "private.yaml"), "")
+
+	tests := []struct {
+		name    string
+		path    string
+		files   []string
+		want    []string
+		wantErr bool
+	}{
+		{"path only", filepath.Join(dir, "bundle"), nil, []string{def, nested}, false},
+		{"path and files", filepath.Join(dir, "bundle"), []string{private}, []string{def, nested, private}, false},
+		{"file repeated takes precedence", filepath.Join(dir, "bundle"), []string{def}, []string{nested, def}, false},
+		{"missing path with files", filepath.Join(dir, "missing"), []string{private}, []string{private}, false},
+		{"missing path", filepath.Join(dir, "missing"), nil, nil, true},
+		{"missing file", "", []string{filepath.Join(dir, "missing.yaml")}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sources(tt.path, tt.files)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad_mergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	public := writeFile(t, filepath.Join(dir, "public.yaml"), `
+Meta:
+  version: 1.2.0
+PatternSignatures:
+  - {signatureid: aws, description: public aws, match: 'AKIA', part: partcontent, enable: 1, confidence-level: 3}
+  - {signatureid: slack, description: public slack, match: 'xox', part: partcontent, enable: 1, confidence-level: 3}
+SimpleSignatures:
+  - {signatureid: rsa, description: public rsa, match: 'id_rsa', part: partfilename, enable: 1, confidence-level: 3}
+`)
+	private := writeFile(t, filepath.Join(dir, "private.yaml"), `
+Meta:
+  version: 0.0.1
+PatternSignatures:
+  - {signatureid: aws, description: private aws, match: 'AKIA', part: partcontent, enable: 1, confidence-level: 3}
+  - {signatureid: internal, description: private internal, match: 'corp_', part: partcontent, enable: 1, confidence-level: 3}
+  - {signatureid: rsa, description: private rsa, match: 'BEGIN RSA', part: partcontent, enable: 1, confidence-level: 3}
+`)
+
+	sigs, version, err := Load([]string{public, private}, 3, Selection{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", version)
+
+	got := map[string][2]string{}
+	for _, s := range sigs {
+		got[s.SignatureID()] = [2]string{s.Description(), s.Source()}
+	}
+	assert.Equal(t, map[string][2]string{
+		"aws":      {"private aws", private},
+		"slack":    {"public slack", public},
+		"rsa":      {"private rsa", private},
+		"internal": {"private internal", private},
+
+		PrivateKeySignatureID: {"Private key", BuiltinSource},
+	}, got)
+}
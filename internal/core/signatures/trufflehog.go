@@ -0,0 +1,123 @@
+package signatures
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+// trufflehogConfig maps to a trufflehog (v3) custom detectors file
+type trufflehogConfig struct {
+	Detectors []trufflehogDetector `yaml:"detectors"`
+}
+
+type trufflehogDetector struct {
+	Name                  string            `yaml:"name"`
+	Regex                 map[string]string `yaml:"regex"`
+	Keywords              []string          `yaml:"keywords"`
+	ExcludeWords          []string          `yaml:"exclude_words"`
+	ExcludeRegexesMatch   []string          `yaml:"exclude_regexes_match"`
+	ExcludeRegexesCapture []string          `yaml:"exclude_regexes_capture"`
+	Verify                []interface{}     `yaml:"verify"`
+	Entropy               float64           `yaml:"entropy"`
+}
+
+// isTrufflehog returns true if the YAML document is a trufflehog custom detectors file
+func isTrufflehog(data []byte) bool {
+	var probe struct {
+		Detectors []interface{} `yaml:"detectors"`
+	}
+	return yaml.Unmarshal(data, &probe) == nil && len(probe.Detectors) > 0
+}
+
+// parseTrufflehog will convert trufflehog custom detectors into signatures. Every regex of a detector becomes
+// a signature of its own, as trufflehog requires all of them to match, which can't be expressed as a signature.
+func parseTrufflehog(data []byte) (SignatureConfig, []LintIssue, error) {
+	var tc trufflehogConfig
+	if err := yaml.Unmarshal(data, &tc); err != nil {
+		return SignatureConfig{}, nil, err
+	}
+
+	var c SignatureConfig
+	var warnings []LintIssue
+	warn := func(id, format string, args ...interface{}) {
+		warnings = append(warnings, LintIssue{id, LintWarning, fmt.Sprintf(format, args...)})
+	}
+
+	for _, d := range tc.Detectors {
+		id := slug(d.Name)
+		if len(d.Regex) == 0 {
+			warn(id, "detector has no regex, skipping it")
+			continue
+		}
+		if len(d.Regex) > 1 {
+			warn(id, "detectors requiring all of their %d regexes to match are not supported, every regex is imported as a separate signature", len(d.Regex))
+		}
+		if len(d.Keywords) > 0 {
+			warn(id, "keywords are not supported, the regex is matched against every file")
+		}
+		if len(d.Verify) > 0 {
+			warn(id, "verification endpoints are not supported, ignoring them")
+		}
+		if len(d.ExcludeRegexesMatch) > 0 || len(d.ExcludeRegexesCapture) > 0 {
+			warn(id, "exclude regexes are not supported, only exclude_words are imported")
+		}
+
+		// map iteration order is random, keep the signatures stable
+		names := make([]string, 0, len(d.Regex))
+		for k := range d.Regex {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			def := SignatureDef{
+				SignatureID:     id,
+				Description:     d.Name,
+				Comment:         "imported from trufflehog",
+				Part:            "partcontent",
+				Entropy:         d.Entropy,
+				Stopwords:       d.ExcludeWords,
+				Enable:          1,
+				ConfidenceLevel: config.DefaultConfig.Global.ConfidenceLevel,
+			}
+			if len(names) > 1 {
+				def.SignatureID = fmt.Sprintf("%s-%s", id, slug(name))
+				def.Description = fmt.Sprintf("%s (%s)", d.Name, name)
+			}
+			// trufflehog reports the first capture group as the secret
+			group := 0
+			if captures(d.Regex[name]) > 0 {
+				group = 1
+			}
+			match, err := withSecretGroup(d.Regex[name], group)
+			if err != nil {
+				warn(def.SignatureID, "%s, using the whole match as the secret", err.Error())
+				match = d.Regex[name]
+			}
+			def.Match = match
+			c.PatternSignatures = append(c.PatternSignatures, def)
+		}
+	}
+	return c, warnings, nil
+}
+
+// captures returns the number of capture groups in the expression
+func captures(expr string) int {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return 0
+	}
+	return re.MaxCap()
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug will turn a name into a signature id, e.g. "Hog Token" => "hog-token"
+func slug(s string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
@@ -0,0 +1,20 @@
+package core
+
+import (
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+)
+
+// scanTargets returns the users and organizations given for the provider of the scan type
+func scanTargets(cfg *config.Config) []string {
+	switch cfg.Global.ScanType {
+	case api.AzureDevOps:
+		return cfg.AzureDevOps.Targets
+	case api.Bitbucket:
+		return cfg.Bitbucket.Targets
+	case api.Gitea:
+		return cfg.Gitea.Targets
+	default:
+		return cfg.Gitlab.Targets
+	}
+}
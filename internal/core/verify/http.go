@@ -0,0 +1,80 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseSize is the part of a response that is checked against the expect and reject expressions
+const maxResponseSize = 64 * 1024
+
+// httpVerifier sends a request with the secret and classifies the response by its status code and body
+type httpVerifier struct {
+	client  *http.Client
+	matcher responseMatcher
+	def     Definition
+}
+
+func newHTTPVerifier(def Definition) (Verifier, error) {
+	if def.Method == "" {
+		def.Method = http.MethodGet
+	}
+	if !strings.HasPrefix(def.Endpoint, "http://") && !strings.HasPrefix(def.Endpoint, "https://") {
+		return nil, fmt.Errorf("http verifier endpoint %q must be an http or https url", def.Endpoint)
+	}
+	m, err := newResponseMatcher(def)
+	if err != nil {
+		return nil, err
+	}
+	return httpVerifier{client: http.DefaultClient, matcher: m, def: def}, nil
+}
+
+// Verify will send the request and classify the response
+func (v httpVerifier) Verify(ctx context.Context, secret string) (string, error) {
+	var body io.Reader
+	if v.def.Body != "" {
+		body = strings.NewReader(expand(v.def.Body, secret))
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(v.def.Method), expand(v.def.Endpoint, secret), body)
+	if err != nil {
+		return "", fmt.Errorf("failed creating the request, %w", err)
+	}
+	for k, val := range v.def.Headers {
+		req.Header.Set(k, expand(val, secret))
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case hasStatus(resp.StatusCode, v.def.InvalidStatus, func(c int) bool { return c == http.StatusUnauthorized || c == http.StatusForbidden }):
+		return StatusInvalid, nil
+	case hasStatus(resp.StatusCode, v.def.ValidStatus, func(c int) bool { return c >= 200 && c < 300 }):
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		if err != nil {
+			return "", fmt.Errorf("failed reading the response, %w", err)
+		}
+		return v.matcher.complete(data), nil
+	default:
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+// hasStatus returns true if the code is one of the given status codes, or matches the default when none are given
+func hasStatus(code int, codes []int, def func(int) bool) bool {
+	if len(codes) == 0 {
+		return def(code)
+	}
+	for _, v := range codes {
+		if v == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// tcpVerifier connects to a service, sends a payload with the secret, e.g. a protocol handshake, and
+// classifies the reply by the expect and reject expressions
+type tcpVerifier struct {
+	matcher responseMatcher
+	def     Definition
+}
+
+func newTCPVerifier(def Definition) (Verifier, error) {
+	if def.Expect == "" && def.Reject == "" {
+		return nil, errors.New("tcp verifier requires an expect or reject expression")
+	}
+	if _, _, err := net.SplitHostPort(def.Endpoint); err != nil {
+		return nil, fmt.Errorf("tcp verifier endpoint %q must be host:port, %w", def.Endpoint, err)
+	}
+	m, err := newResponseMatcher(def)
+	if err != nil {
+		return nil, err
+	}
+	return tcpVerifier{matcher: m, def: def}, nil
+}
+
+// Verify will send the payload and read the reply until either expression matches it, the connection is
+// closed or the context expires
+func (v tcpVerifier) Verify(ctx context.Context, secret string) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", v.def.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if v.def.Body != "" {
+		if _, err := conn.Write([]byte(expand(v.def.Body, secret))); err != nil {
+			return "", fmt.Errorf("failed sending the payload, %w", err)
+		}
+	}
+
+	var reply []byte
+	buf := make([]byte, 4096)
+	for len(reply) < maxResponseSize {
+		n, err := conn.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if status := v.matcher.match(reply); status != "" {
+			return status, nil
+		}
+		if err != nil {
+			break
+		}
+	}
+	if len(reply) == 0 {
+		return "", errors.New("no reply from the service")
+	}
+	return v.matcher.complete(reply), nil
+}
@@ -0,0 +1,244 @@
+// Package verify checks whether the secrets matched by the signatures are live credentials
+package verify
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+)
+
+// The outcome of verifying a secret
+const (
+	StatusVerified = "verified" // the credential is live
+	StatusInvalid  = "invalid"  // the service rejected the credential
+	StatusUnknown  = "unknown"  // the service couldn't be reached or gave an unexpected answer
+)
+
+// secretPlaceholder is replaced with the secret in the endpoint, headers, body and payload of a verifier
+const secretPlaceholder = "{{secret}}"
+
+// workers is the number of secrets verified in the background at the same time
+const workers = 8
+
+// Definition maps to the verifier of a signature within the yaml file
+type Definition struct {
+	Type          string            `yaml:"type"`                     // http or tcp, see Register for adding others
+	Name          string            `yaml:"name,omitempty"`           // key of the endpoint override in the config, defaults to the signature id
+	Endpoint      string            `yaml:"endpoint"`                 // url for http, host:port for tcp
+	Method        string            `yaml:"method,omitempty"`         // http method, defaults to GET
+	Body          string            `yaml:"body,omitempty"`           // http request body or tcp payload
+	Expect        string            `yaml:"expect,omitempty"`         // regex the response must match for the secret to be verified
+	Reject        string            `yaml:"reject,omitempty"`         // regex marking the secret as invalid when the response matches
+	Headers       map[string]string `yaml:"headers,omitempty"`        // http request headers
+	ValidStatus   []int             `yaml:"valid-status,omitempty"`   // http status codes of a live secret, defaults to 2xx
+	InvalidStatus []int             `yaml:"invalid-status,omitempty"` // http status codes of a dead secret, defaults to 401 and 403
+}
+
+// Verifier checks a single secret against the service it belongs to. An error means the outcome is
+// unknown, e.g. the service couldn't be reached.
+type Verifier interface {
+	Verify(ctx context.Context, secret string) (string, error)
+}
+
+// Factory creates a verifier from its definition, with the endpoint already resolved
+type Factory func(def Definition) (Verifier, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		"http": newHTTPVerifier,
+		"tcp":  newTCPVerifier,
+	}
+)
+
+// Register will make a verifier type available to the signatures, replacing any existing one with the same name
+func Register(kind string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(kind)] = f
+}
+
+// Types returns the registered verifier types
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	res := make([]string, 0, len(registry))
+	for k := range registry {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// New will create the verifier of the definition
+func New(def Definition) (Verifier, error) {
+	registryMu.RLock()
+	f, ok := registry[strings.ToLower(def.Type)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown verifier type %q, expected one of %s", def.Type, strings.Join(Types(), ", "))
+	}
+	if def.Endpoint == "" {
+		return nil, fmt.Errorf("verifier %q has no endpoint", def.Type)
+	}
+	return f(def)
+}
+
+// Validate will check that a verifier can be created from the definition
+func Validate(def Definition) error {
+	_, err := New(def)
+	return err
+}
+
+// Result is the outcome of verifying a secret
+type Result struct {
+	Status  string
+	Message string
+}
+
+// Runner verifies the secrets found during a scan. It applies the endpoint overrides from the configuration
+// and remembers the outcome, so that the same secret found in many places is checked once.
+type Runner struct {
+	endpoints map[string]string
+	cache     map[string]*pending
+	slots     chan struct{}
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	timeout   time.Duration
+}
+
+// pending is the outcome of verifying a secret, which is set when done is closed
+type pending struct {
+	done chan struct{}
+	res  Result
+}
+
+// NewRunner will create a runner using the verify section of the configuration
+func NewRunner(cfg config.Verify) *Runner {
+	endpoints := make(map[string]string, len(cfg.Endpoints))
+	for k, v := range cfg.Endpoints {
+		// viper lowercases the keys, lookups are case insensitive
+		endpoints[strings.ToLower(k)] = v
+	}
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = time.Duration(config.DefaultConfig.Verify.Timeout) * time.Second
+	}
+	return &Runner{endpoints: endpoints, cache: make(map[string]*pending), slots: make(chan struct{}, workers), timeout: timeout}
+}
+
+// Go will verify the secret in the background and pass the outcome to done, so that the scan doesn't wait
+// for the services to answer. Wait blocks until all of them are verified.
+func (r *Runner) Go(ctx context.Context, id string, def Definition, secret string, done func(Result)) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.slots <- struct{}{}
+		defer func() { <-r.slots }()
+		done(r.Verify(ctx, id, def, secret))
+	}()
+}
+
+// Wait blocks until the secrets passed to Go are verified
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+// Verify will check the secret with the verifier of the signature. The verifier name defaults to the signature id.
+// A secret which is being verified already waits for the outcome of that.
+func (r *Runner) Verify(ctx context.Context, id string, def Definition, secret string) Result {
+	name := def.Name
+	if name == "" {
+		name = id
+	}
+	if v, ok := r.endpoints[strings.ToLower(name)]; ok {
+		def.Endpoint = v
+	}
+
+	key := name + "\x00" + def.Endpoint + "\x00" + secret
+	r.mu.Lock()
+	p, ok := r.cache[key]
+	if !ok {
+		p = &pending{done: make(chan struct{})}
+		r.cache[key] = p
+	}
+	r.mu.Unlock()
+	if ok {
+		<-p.done
+		return p.res
+	}
+
+	p.res = r.verify(ctx, def, secret)
+	close(p.done)
+	return p.res
+}
+
+func (r *Runner) verify(ctx context.Context, def Definition, secret string) Result {
+	v, err := New(def)
+	if err != nil {
+		return Result{StatusUnknown, err.Error()}
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	status, err := v.Verify(ctx, secret)
+	if err != nil {
+		return Result{StatusUnknown, err.Error()}
+	}
+	return Result{Status: status}
+}
+
+// expand will replace the secret placeholder in the value
+func expand(value, secret string) string {
+	return strings.ReplaceAll(value, secretPlaceholder, secret)
+}
+
+// responseMatcher classifies the response of a service by the expect and reject expressions of a verifier
+type responseMatcher struct {
+	expect *regexp.Regexp
+	reject *regexp.Regexp
+}
+
+func newResponseMatcher(def Definition) (responseMatcher, error) {
+	var m responseMatcher
+	var err error
+	if def.Expect != "" {
+		if m.expect, err = regexp.Compile(def.Expect); err != nil {
+			return m, fmt.Errorf("invalid expect expression %q, %w", def.Expect, err)
+		}
+	}
+	if def.Reject != "" {
+		if m.reject, err = regexp.Compile(def.Reject); err != nil {
+			return m, fmt.Errorf("invalid reject expression %q, %w", def.Reject, err)
+		}
+	}
+	return m, nil
+}
+
+// match will classify the response, returning an empty status if neither expression matches it
+func (m responseMatcher) match(data []byte) string {
+	if m.reject != nil && m.reject.Match(data) {
+		return StatusInvalid
+	}
+	if m.expect != nil && m.expect.Match(data) {
+		return StatusVerified
+	}
+	return ""
+}
+
+// complete will classify the whole response. Unless the reject expression matches it, the response must match
+// the expect expression for the secret to be verified.
+func (m responseMatcher) complete(data []byte) string {
+	if status := m.match(data); status != "" {
+		return status
+	}
+	if m.expect != nil {
+		return StatusInvalid
+	}
+	return StatusVerified
+}
@@ -0,0 +1,196 @@
+package verify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const liveToken = "live-token"
+
+// newTestServer stands in for an API accepting only the live token
+func newTestServer(t *testing.T, calls *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls != nil {
+			atomic.AddInt32(calls, 1)
+		}
+		switch r.Header.Get("Authorization") {
+		case "Bearer " + liveToken:
+			w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+		case "Bearer revoked":
+			w.Write([]byte(`{"ok":false,"error":"token_revoked"}`)) //nolint:errcheck
+		case "Bearer broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestListener stands in for a service with a line based handshake, replying OK to the live token
+func newTestListener(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				line, _ := bufio.NewReader(c).ReadString('\n')
+				if strings.TrimSpace(line) == "AUTH "+liveToken {
+					c.Write([]byte("+OK\r\n")) //nolint:errcheck
+				} else {
+					c.Write([]byte("-ERR invalid password\r\n")) //nolint:errcheck
+				}
+			}(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestHTTPVerifier(t *testing.T) {
+	srv := newTestServer(t, nil)
+	def := Definition{
+		Type:     "http",
+		Endpoint: srv.URL,
+		Headers:  map[string]string{"Authorization": "Bearer {{secret}}"},
+		Reject:   `"ok":false`,
+	}
+	tests := []struct {
+		secret  string
+		want    string
+		wantErr bool
+	}{
+		{liveToken, StatusVerified, false},
+		{"revoked", StatusInvalid, false},
+		{"dead", StatusInvalid, false},
+		{"broken", "", true},
+	}
+	v, err := New(def)
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.secret, func(t *testing.T) {
+			got, err := v.Verify(context.Background(), tt.secret)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTCPVerifier(t *testing.T) {
+	v, err := New(Definition{Type: "tcp", Endpoint: newTestListener(t), Body: "AUTH {{secret}}\r\n", Expect: `^\+OK`})
+	require.NoError(t, err)
+
+	got, err := v.Verify(context.Background(), liveToken)
+	require.NoError(t, err)
+	assert.Equal(t, StatusVerified, got)
+
+	got, err = v.Verify(context.Background(), "dead")
+	require.NoError(t, err)
+	assert.Equal(t, StatusInvalid, got)
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     Definition
+		wantErr string
+	}{
+		{"unknown type", Definition{Type: "smtp", Endpoint: "localhost:25"}, `unknown verifier type "smtp", expected one of http, tcp`},
+		{"no endpoint", Definition{Type: "http"}, `verifier "http" has no endpoint`},
+		{"http url", Definition{Type: "http", Endpoint: "localhost:8080"}, `http verifier endpoint "localhost:8080" must be an http or https url`},
+		{"invalid expect", Definition{Type: "http", Endpoint: "http://localhost", Expect: "("}, "invalid expect expression \"(\", error parsing regexp: missing closing ): `(`"},
+		{"tcp without expressions", Definition{Type: "tcp", Endpoint: "localhost:6379"}, "tcp verifier requires an expect or reject expression"},
+		{"tcp address", Definition{Type: "tcp", Endpoint: "localhost", Expect: "OK"}, `tcp verifier endpoint "localhost" must be host:port, address localhost: missing port in address`},
+		{"valid", Definition{Type: "HTTP", Endpoint: "https://api.example.com/user"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.def)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+type staticVerifier string
+
+func (v staticVerifier) Verify(context.Context, string) (string, error) {
+	return string(v), nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("static", func(def Definition) (Verifier, error) { return staticVerifier(def.Endpoint), nil })
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "static")
+		registryMu.Unlock()
+	})
+
+	res := NewRunner(config.Verify{}).Verify(context.Background(), "sig", Definition{Type: "static", Endpoint: StatusVerified}, "secret")
+	assert.Equal(t, Result{Status: StatusVerified}, res)
+}
+
+func TestRunner_Verify(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, &calls)
+	// the signature points to the real service, the configuration to the stand-in server
+	def := Definition{Type: "http", Endpoint: "https://api.example.invalid/auth", Headers: map[string]string{"Authorization": "Bearer {{secret}}"}}
+	r := NewRunner(config.Verify{Endpoints: map[string]string{"example-token": srv.URL}})
+
+	assert.Equal(t, Result{Status: StatusVerified}, r.Verify(context.Background(), "Example-Token", def, liveToken))
+	assert.Equal(t, Result{Status: StatusVerified}, r.Verify(context.Background(), "Example-Token", def, liveToken))
+	assert.Equal(t, Result{Status: StatusInvalid}, r.Verify(context.Background(), "Example-Token", def, "dead"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "the outcome of a secret is cached")
+
+	// the verifier name takes precedence over the signature id
+	def.Name = "example"
+	r = NewRunner(config.Verify{Endpoints: map[string]string{"example": srv.URL}})
+	assert.Equal(t, Result{Status: StatusVerified}, r.Verify(context.Background(), "example-token", def, liveToken))
+
+	broken := NewRunner(config.Verify{Endpoints: map[string]string{"example": "http://127.0.0.1:1"}})
+	res := broken.Verify(context.Background(), "example-token", def, liveToken)
+	assert.Equal(t, StatusUnknown, res.Status)
+	assert.NotEmpty(t, res.Message)
+}
+
+func TestRunner_Go(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, &calls)
+	def := Definition{Type: "http", Endpoint: srv.URL, Headers: map[string]string{"Authorization": "Bearer {{secret}}"}}
+	r := NewRunner(config.Verify{})
+
+	// the same secret found by many workers at once is verified once
+	results := make(chan Result, 20)
+	for i := 0; i < cap(results); i++ {
+		r.Go(context.Background(), "example-token", def, liveToken, func(res Result) { results <- res })
+	}
+	r.Wait()
+	close(results)
+	for res := range results {
+		assert.Equal(t, Result{Status: StatusVerified}, res)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -73,6 +73,11 @@ func (l *logger) SetDebug(d bool) *logger {
 	return l
 }
 
+// IsDebug returns true if debug output is enabled, for skipping the work of preparing debug messages
+func (l *logger) IsDebug() bool {
+	return l.debug
+}
+
 // Log is a generic printer for sending data to stdout. It does not do traditional syslog logging
 func (l *logger) Log(level int, format string, args ...interface{}) {
 	l.Lock()
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core/finding"
+	"github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// ExitCodeFindings is returned by the application when the scan found secrets over the failure threshold
+const ExitCodeFindings = 2
+
+// ThresholdError is returned when findings match the fail-on-severity and fail-on-tags settings
+type ThresholdError struct {
+	Severity string
+	Tags     []string
+	Count    int
+}
+
+func (e ThresholdError) Error() string {
+	var conditions []string
+	if e.Severity != "" {
+		conditions = append(conditions, fmt.Sprintf("severity %s or higher", e.Severity))
+	}
+	if len(e.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf("tags %s", strings.Join(e.Tags, ", ")))
+	}
+	secrets := fmt.Sprintf("found %d %s", e.Count, util.Pluralize(e.Count, "secret", "secrets"))
+	if len(conditions) == 0 {
+		return secrets
+	}
+	return fmt.Sprintf("%s with %s", secrets, strings.Join(conditions, " and "))
+}
+
+// ExitCode returns the exit code the application should terminate with
+func (e ThresholdError) ExitCode() int {
+	return ExitCodeFindings
+}
+
+// Threshold will return a ThresholdError if any of the findings meets the failure threshold. Without
+// fail-on-severity and fail-on-tags set, findings don't fail the scan.
+func Threshold(st *session.State, cfg config.Global) error {
+	if cfg.FailOnSeverity == "" && len(cfg.FailOnTags) == 0 {
+		return nil
+	}
+	var cnt int
+	for _, f := range st.GetFindings() {
+		if failsOn(f, cfg.FailOnSeverity, cfg.FailOnTags) {
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		return nil
+	}
+	return ThresholdError{Severity: cfg.FailOnSeverity, Tags: cfg.FailOnTags, Count: cnt}
+}
+
+func failsOn(f *finding.Finding, severity string, tags []string) bool {
+	if !signatures.SeverityAtLeast(f.Severity, severity) {
+		return false
+	}
+	return len(tags) == 0 || signatures.HasAnyTag(f.Tags, f.Category, tags)
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core/finding"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreshold(t *testing.T) {
+	st := &session.State{Mutex: &sync.Mutex{}, Findings: map[string]*finding.Finding{
+		"1": {SecretID: "1", Severity: "critical", Category: "cloud", Tags: []string{"aws"}},
+		"2": {SecretID: "2", Severity: "high", Category: "database"},
+		"3": {SecretID: "3", Severity: "low", Tags: []string{"generic"}},
+	}}
+	tests := []struct {
+		name    string
+		cfg     config.Global
+		wantErr string
+	}{
+		{"no threshold", config.Global{}, ""},
+		{"severity", config.Global{FailOnSeverity: "high"}, "found 2 secrets with severity high or higher"},
+		{"severity not met", config.Global{FailOnSeverity: "critical", FailOnTags: []string{"database"}}, ""},
+		{"tags", config.Global{FailOnTags: []string{"aws", "gen*"}}, "found 2 secrets with tags aws, gen*"},
+		{"severity and tags", config.Global{FailOnSeverity: "medium", FailOnTags: []string{"cloud"}}, "found 1 secret with severity medium or higher and tags cloud"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Threshold(st, tt.cfg)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			var e ThresholdError
+			assert.ErrorAs(t, err, &e)
+			assert.Equal(t, ExitCodeFindings, e.ExitCode())
+		})
+	}
+
+	assert.EqualError(t, ThresholdError{Count: 3}, "found 3 secrets")
+}
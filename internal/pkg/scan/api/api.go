@@ -3,11 +3,18 @@ package api
 type ScanType string
 
 const (
+	AzureDevOps      ScanType = "azure-devops"
+	Bitbucket        ScanType = "bitbucket"
+	Gitea            ScanType = "gitea"
+	GitRemotes       ScanType = "git-remotes"
 	Github           ScanType = "github"
 	GithubEnterprise ScanType = "github-enterprise"
 	Gitlab           ScanType = "gitlab"
+	Image            ScanType = "image"
 	LocalGit         ScanType = "localGit"
 	LocalPath        ScanType = "localpath"
+	Signatures       ScanType = "signatures"
+	Stdin            ScanType = "stdin"
 	Unknown          ScanType = "unknown" // for testing
 	UpdateSignatures ScanType = "update-signatures"
 )
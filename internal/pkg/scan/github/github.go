@@ -96,7 +96,7 @@ func (g Github) Run() error {
 		log.Important("Press Ctrl+C to stop web server and exit.")
 		select {}
 	}
-	return nil
+	return output.Threshold(sess.State, sess.Config.Global)
 }
 
 var _ api.Scanner = (*Github)(nil)
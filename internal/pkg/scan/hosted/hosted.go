@@ -1,4 +1,7 @@
-package gitlab
+// Package hosted scans the repositories of the users and organizations on the git hosting services,
+// GitLab, Bitbucket, Gitea and Azure DevOps, which are all enumerated through the provider client of the
+// scan type
+package hosted
 
 import (
 	"context"
@@ -15,11 +18,11 @@ import (
 	"github.com/rumenvasilev/rvsecret/internal/webserver"
 )
 
-type Gitlab struct {
+type Hosted struct {
 	Cfg *config.Config
 }
 
-func (g Gitlab) Run() error {
+func (g Hosted) Run() error {
 	cfg := g.Cfg
 	log := log.Log
 	ctx := context.Background()
@@ -59,7 +62,7 @@ func (g Gitlab) Run() error {
 		log.Important("Press Ctrl+C to stop web server and exit.")
 		select {}
 	}
-	return nil
+	return output.Threshold(sess.State, sess.Config.Global)
 }
 
-var _ api.Scanner = (*Gitlab)(nil)
+var _ api.Scanner = (*Hosted)(nil)
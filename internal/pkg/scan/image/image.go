@@ -0,0 +1,79 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core"
+	"github.com/rumenvasilev/rvsecret/internal/core/banner"
+	"github.com/rumenvasilev/rvsecret/internal/core/image"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/output"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+	"github.com/rumenvasilev/rvsecret/internal/webserver"
+)
+
+type Image struct {
+	Cfg *config.Config
+}
+
+func (i Image) Run() error {
+	cfg := i.Cfg
+	log := log.Log
+	ctx := context.Background()
+	if len(cfg.Local.Images) == 0 {
+		return errors.New("no images to scan, pass the path of a docker save tarball or an OCI image layout")
+	}
+
+	// create session
+	sess, err := session.NewWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Start webserver
+	if cfg.Global.WebServer && !cfg.Global.Silent {
+		ws := webserver.New(ctx, *cfg, sess.State)
+		go ws.Start()
+	}
+
+	log.Debug("We have these images: %s", cfg.Local.Images)
+
+	if cfg.Global.Debug {
+		log.Debug(config.PrintDebug(sess.SignatureVersion))
+	}
+
+	banner.HeaderInfo(cfg.Global, sess.State.Stats.StartedAt.Format(time.RFC3339), len(sess.Signatures))
+	ctxworker := context.WithValue(ctx, core.TID, 0)
+	for _, p := range cfg.Local.Images {
+		r, err := image.Open(p)
+		if err != nil {
+			log.Error("Cannot read image: %s", err.Error())
+			continue
+		}
+		for _, img := range r.Images {
+			log.Important("Scanning image %s", img.Name)
+			core.AnalyzeImage(ctxworker, sess, img)
+		}
+		r.Close()
+	}
+
+	sess.Finish()
+
+	err = output.Summary(sess.State, sess.Config.Global, sess.SignatureVersion)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Global.WebServer && !cfg.Global.Silent {
+		log.Important("Press Ctrl+C to stop web server and exit.")
+		select {}
+	}
+
+	return output.Threshold(sess.State, sess.Config.Global)
+}
+
+var _ api.Scanner = (*Image)(nil)
@@ -60,7 +60,7 @@ func (l LocalGit) Run() error {
 		log.Important("Press Ctrl+C to stop web server and exit.")
 		select {}
 	}
-	return nil
+	return output.Threshold(sess.State, sess.Config.Global)
 }
 
 var _ api.Scanner = (*LocalGit)(nil)
@@ -0,0 +1,91 @@
+package localpath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core/matchfile"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+)
+
+const gitignoreFile = ".gitignore"
+
+// filter decides which files of a directory are scanned. The include and exclude glob patterns and the
+// .gitignore files are relative to the scanned directory.
+type filter struct {
+	skippablePath []string
+	include       []string
+	exclude       []string
+	gitignore     []gitignore.Pattern
+	useGitignore  bool
+}
+
+func newFilter(cfg *config.Config) *filter {
+	return &filter{
+		skippablePath: cfg.Global.SkippablePath,
+		include:       cfg.Local.Include,
+		exclude:       cfg.Local.Exclude,
+		useGitignore:  cfg.Local.RespectGitignore,
+	}
+}
+
+// validate will return an error for malformed glob patterns
+func (f *filter) validate() error {
+	for _, p := range append(append([]string{}, f.include...), f.exclude...) {
+		if err := matchfile.ValidateGlob(p); err != nil {
+			return fmt.Errorf("invalid pattern %q, %w", p, err)
+		}
+	}
+	return nil
+}
+
+// skip reports whether the file or directory at path, whose slash separated path relative to the scanned
+// directory is rel, is left out. Directories are only checked against the exclude patterns, so that the
+// files within them can match the include patterns.
+func (f *filter) skip(path, rel string, isDir bool) bool {
+	if matchfile.HasSkippablePath(filepath.ToSlash(path), f.skippablePath) {
+		return true
+	}
+	if f.useGitignore && gitignore.NewMatcher(f.gitignore).Match(strings.Split(rel, "/"), isDir) {
+		return true
+	}
+	for _, p := range f.exclude {
+		if matchfile.Glob(p, rel) {
+			return true
+		}
+	}
+	if isDir || len(f.include) == 0 {
+		return false
+	}
+	for _, p := range f.include {
+		if matchfile.Glob(p, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+// enter will load the .gitignore file of the directory, whose patterns apply to the files within it
+func (f *filter) enter(dir, rel string) {
+	if !f.useGitignore {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, gitignoreFile))
+	if err != nil {
+		return
+	}
+	var domain []string
+	if rel != "." {
+		domain = strings.Split(rel, "/")
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		f.gitignore = append(f.gitignore, gitignore.ParsePattern(line, domain))
+	}
+}
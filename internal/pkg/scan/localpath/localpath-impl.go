@@ -4,9 +4,6 @@ import (
 	"context"
 	"os"
 	"path/filepath"
-	"strings"
-	"sync"
-	"time"
 
 	"github.com/rumenvasilev/rvsecret/internal/core"
 	"github.com/rumenvasilev/rvsecret/internal/core/api"
@@ -16,75 +13,73 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-// ScanDir will scan a directory for all the files and then kick a file scan on each of them
-func scanDir(path string, sess *session.Session) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3600*time.Second)
-	defer cancel()
-
-	// get an slice of of all paths
-	files, err := search(ctx, path, sess.Config.Global.SkippablePath, sess)
-	if err != nil {
-		log.Log.Error("There is an error scanning %s: %s", path, err.Error())
-	}
-
-	maxThreads := 100
-	sem := make(chan struct{}, maxThreads)
-
-	var wg sync.WaitGroup
-
-	wg.Add(len(files))
-	for _, file := range files {
-		sem <- struct{}{}
-		go func(f string) {
-			defer wg.Done()
-
-			// scan the specific file if it is found to be a valid candidate
-			core.AnalyzeObject(ctx, sess, nil, nil, f, api.Repository{})
-			<-sem
-		}(file)
+// ScanDir will walk the directory and scan its files as they are found, with as many workers as the
+// configured threads. The findings are added to the session state. Only the paths waiting for a worker are
+// held in memory, so the size of the tree doesn't matter. It returns the error of the context once it's
+// cancelled, the files left are not scanned then.
+func scanDir(ctx context.Context, path string, sess *session.Session) error {
+	log.Log.Important("Scanning %s", path)
+	threads := sess.Config.Global.Threads
+	if threads < 1 {
+		threads = 1
 	}
-
-	wg.Wait()
-}
-
-// Search will walk the path or a given directory and append each viable path to an array
-func search(ctx context.Context, root string, skippablePath []string, sess *session.Session) ([]string, error) {
-	log.Log.Important("Enumerating Paths")
 	g, ctx := errgroup.WithContext(ctx)
-	paths := make(chan string, 20)
+	paths := make(chan string, threads)
 
-	// get all the paths within a tree
 	g.Go(func() error {
 		defer close(paths)
+		return walk(ctx, path, newFilter(sess.Config), paths)
+	})
 
-		return filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
-			// This will check against the combined list of directories that we want to exclude
-			// There is the stock list that we pre-defined and then user have the ability to add to this list via the commandline
-			for _, p := range skippablePath {
-				if strings.HasPrefix(path, p) {
-					return nil
+	for i := 0; i < threads; i++ {
+		ctxworker := context.WithValue(ctx, core.TID, i)
+		g.Go(func() error {
+			for p := range paths {
+				if err := ctx.Err(); err != nil {
+					return err
 				}
+				// scan the specific file if it is found to be a valid candidate
+				core.AnalyzeObject(ctxworker, sess, nil, nil, p, api.Repository{})
 			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
 
-			if os.IsPermission(err) {
-				return nil
-			}
-			if !fi.Mode().IsRegular() {
-				return nil
-			}
-
-			select {
-			case paths <- path:
-			case <-ctx.Done():
-				return ctx.Err()
+// walk will send every file of the tree under root that isn't filtered out to paths, until the context
+// is cancelled
+func walk(ctx context.Context, root string, f *filter, paths chan<- string) error {
+	return filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			// e.g. permission errors, the rest of the tree is still walked
+			log.Log.Debug("Cannot read %s, %s", path, err.Error())
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		// This will check against the combined list of directories that we want to exclude
+		// There is the stock list that we pre-defined and then user have the ability to add to this list via the commandline
+		if fi.IsDir() {
+			if rel != "." && f.skip(path, rel, true) {
+				return filepath.SkipDir
 			}
+			f.enter(path, rel)
 			return nil
-		})
+		}
+		if !fi.Mode().IsRegular() || f.skip(path, rel, false) {
+			return nil
+		}
+
+		select {
+		case paths <- path:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
 	})
-
-	var result []string
-	for r := range paths {
-		result = append(result, r)
-	}
-	return result, g.Wait()
 }
@@ -0,0 +1,98 @@
+package localpath
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTree = map[string]string{
+	".gitignore":                  "*.log\n/build/\n# comment\n!keep.log\n",
+	"app.yaml":                    "",
+	"debug.log":                   "",
+	"keep.log":                    "",
+	"build/out.yaml":              "",
+	"deploy/k8s/secret.yml":       "",
+	"deploy/k8s/.gitignore":       "secret.yml\n",
+	"deploy/build/notes.txt":      "",
+	"pkg/testdata/key.pem":        "",
+	"pkg/main.go":                 "",
+	"vendor/cache/gems/key.pem":   "",
+	"myvendor/cache/gems/key.pem": "",
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range testTree {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	tests := []struct {
+		name  string
+		local config.Local
+		want  []string
+	}{
+		{"all", config.Local{}, []string{".gitignore", "app.yaml", "build/out.yaml", "debug.log", "deploy/build/notes.txt", "deploy/k8s/.gitignore", "deploy/k8s/secret.yml", "keep.log", "myvendor/cache/gems/key.pem", "pkg/main.go", "pkg/testdata/key.pem"}},
+		{"include", config.Local{Include: []string{"**/*.{yaml,yml}"}}, []string{"app.yaml", "build/out.yaml", "deploy/k8s/secret.yml"}},
+		{"exclude", config.Local{Exclude: []string{"**/testdata/**", "*.log", "deploy"}}, []string{".gitignore", "app.yaml", "build/out.yaml", "myvendor/cache/gems/key.pem", "pkg/main.go"}},
+		{"include and exclude", config.Local{Include: []string{"**/*.pem"}, Exclude: []string{"pkg/**"}}, []string{"myvendor/cache/gems/key.pem"}},
+		{"gitignore", config.Local{RespectGitignore: true}, []string{".gitignore", "app.yaml", "deploy/build/notes.txt", "deploy/k8s/.gitignore", "keep.log", "myvendor/cache/gems/key.pem", "pkg/main.go", "pkg/testdata/key.pem"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Local: tt.local, Global: config.Global{SkippablePath: []string{"vendor/cache"}}}
+			f := newFilter(cfg)
+			require.NoError(t, f.validate())
+			files, err := collect(context.Background(), root+"/", f)
+			require.NoError(t, err)
+			var got []string
+			for _, file := range files {
+				rel, err := filepath.Rel(root, file)
+				require.NoError(t, err)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWalk_cancelled(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(root, fmt.Sprintf("%d.txt", i)), nil, 0600))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// nothing is read from paths, so the walk blocks on the first file unless it sees the cancellation
+	err := walk(ctx, root, newFilter(&config.Config{}), make(chan string))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func collect(ctx context.Context, root string, f *filter) ([]string, error) {
+	paths := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(paths)
+		errc <- walk(ctx, root, f, paths)
+	}()
+	var files []string
+	for p := range paths {
+		files = append(files, p)
+	}
+	return files, <-errc
+}
+
+func TestFilter_validate(t *testing.T) {
+	f := newFilter(&config.Config{Local: config.Local{Exclude: []string{"[a-"}}})
+	assert.Error(t, f.validate())
+}
@@ -2,6 +2,11 @@ package localpath
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
@@ -26,6 +31,9 @@ func (l Localpath) Run() error {
 	ctx := context.Background()
 	// exclude the .git directory from local scans as it is not handled properly here
 	cfg.Global.SkippablePath = util.AppendIfMissing(cfg.Global.SkippablePath, ".git/")
+	if err := newFilter(cfg).validate(); err != nil {
+		return err
+	}
 
 	// create session
 	sess, err := session.NewWithConfig(cfg)
@@ -48,17 +56,26 @@ func (l Localpath) Run() error {
 	// By default we display a header to the user giving basic info about application. This will not be displayed
 	// during a silent run which is the default when using this in an automated fashion.
 	banner.HeaderInfo(cfg.Global, sess.State.Stats.StartedAt.Format(time.RFC3339), len(sess.Signatures))
-	ctxworker := context.WithValue(ctx, core.TID, 0)
+	scanCtx, cancel := scanContext(ctx, cfg.Local.Timeout)
+	defer cancel()
 	for _, p := range cfg.Local.Paths {
+		if scanCtx.Err() != nil {
+			break
+		}
 		if util.PathExists(p) {
 			last := p[len(p)-1:]
 			if last == "/" {
-				scanDir(p, sess)
+				if err := scanDir(scanCtx, p, sess); err != nil && scanCtx.Err() == nil {
+					log.Error("There is an error scanning %s: %s", p, err.Error())
+				}
 			} else {
-				core.AnalyzeObject(ctxworker, sess, nil, nil, p, coreapi.Repository{})
+				core.AnalyzeObject(context.WithValue(scanCtx, core.TID, 0), sess, nil, nil, p, coreapi.Repository{})
 			}
 		}
 	}
+	scanErr := interrupted(scanCtx, cfg.Local.Timeout)
+	// the web server is stopped with Ctrl+C
+	cancel()
 
 	sess.Finish()
 
@@ -72,7 +89,43 @@ func (l Localpath) Run() error {
 		select {}
 	}
 
-	return nil
+	if scanErr != nil {
+		return scanErr
+	}
+	return output.Threshold(sess.State, sess.Config.Global)
+}
+
+// scanContext returns the context of the scan, which is cancelled on Ctrl+C or SIGTERM, and after the
+// timeout in seconds unless it's 0. A second Ctrl+C exits right away.
+func scanContext(ctx context.Context, timeout int) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	cancel := stop
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		cancel = func() {
+			cancelTimeout()
+			stop()
+		}
+	}
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, cancel
+}
+
+// interrupted returns the error for scans that were stopped before all the files were scanned, so that
+// they don't pass as clean
+func interrupted(ctx context.Context, timeout int) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return fmt.Errorf("the scan timed out after %d seconds, not all files were scanned", timeout)
+	default:
+		return errors.New("the scan was interrupted, not all files were scanned")
+	}
 }
 
 var _ api.Scanner = (*Localpath)(nil)
@@ -6,9 +6,12 @@ import (
 	"github.com/rumenvasilev/rvsecret/internal/config"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/github"
-	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/gitlab"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/hosted"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/image"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/localgit"
 	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/localpath"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/remotes"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/stdin"
 )
 
 func New(cfg *config.Config) api.Scanner {
@@ -17,10 +20,16 @@ func New(cfg *config.Config) api.Scanner {
 		return localpath.Localpath{Cfg: cfg}
 	case api.LocalGit:
 		return localgit.LocalGit{Cfg: cfg}
+	case api.GitRemotes:
+		return remotes.GitRemotes{Cfg: cfg}
 	case api.GithubEnterprise, api.Github:
 		return github.Github{Cfg: cfg}
-	case api.Gitlab:
-		return gitlab.Gitlab{Cfg: cfg}
+	case api.Gitlab, api.Bitbucket, api.Gitea, api.AzureDevOps:
+		return hosted.Hosted{Cfg: cfg}
+	case api.Image:
+		return image.Image{Cfg: cfg}
+	case api.Stdin:
+		return stdin.Stdin{Cfg: cfg}
 	default:
 		return Unsupported{}
 	}
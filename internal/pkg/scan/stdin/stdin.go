@@ -0,0 +1,112 @@
+package stdin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core"
+	"github.com/rumenvasilev/rvsecret/internal/core/banner"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/output"
+	"github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+	"github.com/rumenvasilev/rvsecret/internal/webserver"
+)
+
+// DefaultName is the name of the file the data is reported as, unless another one is set
+const DefaultName = "stdin"
+
+type Stdin struct {
+	Cfg *config.Config
+}
+
+func (s Stdin) Run() error {
+	cfg := s.Cfg
+	log := log.Log
+	ctx := context.Background()
+	name := cfg.Local.StdinName
+	if name == "" {
+		name = DefaultName
+	}
+
+	// reading from the terminal would wait for input the user isn't aware of
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeCharDevice != 0 {
+		return errors.New("nothing to scan, pipe the data to stdin, e.g. cat build.log | rvsecret scan stdin")
+	}
+	data, err := read(os.Stdin, cfg.Global.MaxFileSize*1024*1024)
+	if err != nil {
+		return err
+	}
+
+	// create session
+	sess, err := session.NewWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Start webserver
+	if cfg.Global.WebServer && !cfg.Global.Silent {
+		ws := webserver.New(ctx, *cfg, sess.State)
+		go ws.Start()
+	}
+
+	log.Debug("Read %d bytes from stdin as %s", len(data), name)
+
+	if cfg.Global.Debug {
+		log.Debug(config.PrintDebug(sess.SignatureVersion))
+	}
+
+	banner.HeaderInfo(cfg.Global, sess.State.Stats.StartedAt.Format(time.RFC3339), len(sess.Signatures))
+	ctxworker := context.WithValue(ctx, core.TID, 0)
+	core.AnalyzeContent(ctxworker, sess, name, data)
+
+	sess.Finish()
+
+	err = output.Summary(sess.State, sess.Config.Global, sess.SignatureVersion)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Global.WebServer && !cfg.Global.Silent {
+		log.Important("Press Ctrl+C to stop web server and exit.")
+		select {}
+	}
+
+	return threshold(sess.State, sess.Config.Global)
+}
+
+// threshold fails the scan on any finding, as the hooks piping data to it expect, unless fail-on-severity
+// or fail-on-tags are set
+func threshold(st *session.State, cfg config.Global) error {
+	if cfg.FailOnSeverity != "" || len(cfg.FailOnTags) > 0 {
+		return output.Threshold(st, cfg)
+	}
+	if n := len(st.GetFindings()); n > 0 {
+		return output.ThresholdError{Count: n}
+	}
+	return nil
+}
+
+// read will read all of r, failing when there is more than limit bytes. Unlike files on disk, data that
+// is too large can't be ignored silently, since the scan would pass without having looked at it.
+func read(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin, %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("stdin is larger than %d MB, raise --max-file-size to scan it", limit/1024/1024)
+	}
+	return data, nil
+}
+
+var _ api.Scanner = (*Stdin)(nil)
@@ -0,0 +1,87 @@
+package stdin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core"
+	"github.com/rumenvasilev/rvsecret/internal/core/finding"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	data, err := read(strings.NewReader("password: Tq4mW9zK2xLp7vRb"), 26)
+	require.NoError(t, err)
+	assert.Equal(t, "password: Tq4mW9zK2xLp7vRb", string(data))
+
+	_, err = read(strings.NewReader("password: Tq4mW9zK2xLp7vRb"), 25)
+	assert.Error(t, err)
+
+	_, err = read(strings.NewReader(strings.Repeat("a", 2*1024*1024)), 1024*1024)
+	assert.EqualError(t, err, "stdin is larger than 1 MB, raise --max-file-size to scan it")
+}
+
+func TestThreshold(t *testing.T) {
+	st := &session.State{Mutex: &sync.Mutex{}, Findings: map[string]*finding.Finding{
+		"1": {SecretID: "1", Severity: "low"},
+	}}
+	assert.EqualError(t, threshold(st, config.Global{}), "found 1 secret")
+	assert.NoError(t, threshold(st, config.Global{FailOnSeverity: "high"}))
+	assert.EqualError(t, threshold(st, config.Global{FailOnSeverity: "low"}), "found 1 secret with severity low or higher")
+
+	st.Findings = map[string]*finding.Finding{}
+	assert.NoError(t, threshold(st, config.Global{}))
+}
+
+func TestAnalyzeContent_notIgnored(t *testing.T) {
+	log.NewNoopLogger()
+	sigs := filepath.Join(t.TempDir(), "signatures.yaml")
+	require.NoError(t, os.WriteFile(sigs, []byte(`
+PatternSignatures:
+  - signatureid: generic-password
+    description: Password assignment
+    match: 'password:\s*(?P<secret>\S{8,})'
+    part: partcontent
+    enable: 1
+    confidence-level: 3
+`), 0600))
+
+	// the names and data that files are ignored for
+	tests := []struct {
+		name, path string
+		data       []byte
+	}{
+		{"test file", "test/app.env", []byte("password: Tq4mW9zK2xLp7vRb\n")},
+		{"skippable extension", "app.log", []byte("password: Tq4mW9zK2xLp7vRb\n")},
+		{"skippable path", "node_modules/app.env", []byte("password: Tq4mW9zK2xLp7vRb\n")},
+		{"binary", DefaultName, []byte("\x7fELF\x02\x01\npassword: Tq4mW9zK2xLp7vRb\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Global: config.Global{
+					ConfidenceLevel: 3,
+					MaxFileSize:     10,
+					SkippableExt:    []string{"log"},
+					SkippablePath:   []string{"node_modules/"},
+				},
+				Signatures: config.Signatures{File: []string{sigs}},
+			}
+			sess, err := session.NewWithConfig(cfg)
+			require.NoError(t, err)
+
+			core.AnalyzeContent(context.WithValue(context.Background(), core.TID, 0), sess, tt.path, tt.data)
+			sess.Finish()
+			assert.Len(t, sess.State.Findings, 1)
+			assert.Equal(t, 0, sess.State.Stats.FilesIgnored)
+		})
+	}
+}
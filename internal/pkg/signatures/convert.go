@@ -0,0 +1,58 @@
+package signatures
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Convert will read a gitleaks or trufflehog rules file and write the equivalent signatures to the output
+// file, or stdout if empty. Rule options that couldn't be converted are written as comments at the top.
+func Convert(file, output string) error {
+	c, warnings, err := coresignatures.Convert(file)
+	if err != nil {
+		return fmt.Errorf("failed to convert signatures file %s: %w", file, err)
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("Converted from %s", filepath.Base(file))
+	if output == "" {
+		return writeWithHeader(os.Stdout, header, data, warnings)
+	}
+	if err := writeFile(output, header, data, warnings); err != nil {
+		return err
+	}
+
+	for _, v := range warnings {
+		log.Log.Warn("%s", v.String())
+	}
+	cnt := len(c.SimpleSignatures) + len(c.PatternSignatures) + len(c.SafeFunctionSignatures)
+	log.Log.Important("Converted %d %s to %s with %d %s.", cnt, util.Pluralize(cnt, "signature", "signatures"), output,
+		len(warnings), util.Pluralize(len(warnings), "warning", "warnings"))
+	return nil
+}
+
+// writeWithHeader will write the data prefixed by comments with the header and the warnings
+func writeWithHeader(out io.Writer, header string, data []byte, warnings []coresignatures.LintIssue) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", header)
+	for _, v := range warnings {
+		fmt.Fprintf(&sb, "# warning: %s\n", v.String())
+	}
+	if _, err := io.WriteString(out, sb.String()); err != nil {
+		return err
+	}
+	_, err := out.Write(data)
+	return err
+}
@@ -0,0 +1,76 @@
+package signatures
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// Export will write the signatures enabled for the given confidence level and selection in the requested
+// format to the output file, or stdout if empty. Anything that can't be expressed in the format is written
+// as comments at the top. Semgrep tests are written next to the output file, as expected by `semgrep --test`.
+func Export(files []string, level int, sel coresignatures.Selection, format, output string) error {
+	c, err := coresignatures.Export(files, level, sel)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	var warnings []coresignatures.LintIssue
+	switch strings.ToLower(format) {
+	case coresignatures.FormatYAML:
+		data, warnings, err = coresignatures.ExportYAML(c)
+	case coresignatures.FormatGitleaks:
+		data, warnings, err = coresignatures.ExportGitleaks(c)
+	case coresignatures.FormatSemgrep:
+		data, warnings, err = coresignatures.ExportSemgrep(c)
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(coresignatures.ExportFormats, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export signatures, %w", err)
+	}
+
+	header := "Exported from rvsecret signatures"
+	if c.Meta.Version != "" {
+		header += " " + c.Meta.Version
+	}
+	if output == "" {
+		return writeWithHeader(os.Stdout, header, data, warnings)
+	}
+
+	if err := writeFile(output, header, data, warnings); err != nil {
+		return err
+	}
+	if strings.EqualFold(format, coresignatures.FormatSemgrep) {
+		if tests := coresignatures.ExportSemgrepTests(c); tests != nil {
+			testFile := strings.TrimSuffix(output, filepath.Ext(output)) + ".txt"
+			if err := os.WriteFile(testFile, tests, 0644); err != nil {
+				return err
+			}
+			log.Log.Info("Wrote semgrep tests to %s.", testFile)
+		}
+	}
+
+	for _, v := range warnings {
+		log.Log.Warn("%s", v.String())
+	}
+	cnt := len(c.SimpleSignatures) + len(c.PatternSignatures) + len(c.SafeFunctionSignatures)
+	log.Log.Important("Exported %d %s to %s with %d %s.", cnt, util.Pluralize(cnt, "signature", "signatures"), output,
+		len(warnings), util.Pluralize(len(warnings), "warning", "warnings"))
+	return nil
+}
+
+func writeFile(output, header string, data []byte, warnings []coresignatures.LintIssue) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+	return writeWithHeader(f, header, data, warnings)
+}
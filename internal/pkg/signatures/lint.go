@@ -0,0 +1,47 @@
+package signatures
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// Lint will validate the signatures files and print any issues found in them
+func Lint(files []string) error {
+	var errCnt int
+	for _, f := range files {
+		issues, err := coresignatures.LintFile(f)
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			log.Log.Important("%s: no issues found.", f)
+			continue
+		}
+		log.Log.Important("%s: %d %s found.", f, len(issues), util.Pluralize(len(issues), "issue", "issues"))
+		writeLintReport(os.Stdout, issues)
+		for _, v := range issues {
+			if v.Level == coresignatures.LintError {
+				errCnt++
+			}
+		}
+	}
+	if errCnt > 0 {
+		return fmt.Errorf("%d %s must be fixed before the signatures can be used", errCnt, util.Pluralize(errCnt, "error", "errors"))
+	}
+	return nil
+}
+
+func writeLintReport(out io.Writer, issues []coresignatures.LintIssue) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LEVEL\tSIGNATURE ID\tMESSAGE") //nolint:errcheck
+	for _, v := range issues {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.Level, v.SignatureID, v.Message) //nolint:errcheck
+	}
+	w.Flush() //nolint:errcheck
+}
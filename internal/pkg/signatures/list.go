@@ -0,0 +1,117 @@
+package signatures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+)
+
+// List will print all the signatures found in the signature files, along with whether they are enabled
+// for the given confidence level and how many of them would be loaded during a scan
+func List(files []string, level int, sel coresignatures.Selection, jsonOutput bool) error {
+	infos, stats, err := coresignatures.Inspect(files, level, sel)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return writeJSON(os.Stdout, struct {
+			Signatures []coresignatures.SignatureInfo `json:"signatures"`
+			Stats      coresignatures.InspectStats    `json:"stats"`
+			Level      int                            `json:"confidence-level"`
+		}{infos, stats, level})
+	}
+
+	writeSignatureList(os.Stdout, infos)
+	log.Log.Important("%s, confidence level %d.", stats.String(), level)
+	return nil
+}
+
+// Show will print the details of a single signature
+func Show(files []string, id string, level int, sel coresignatures.Selection, jsonOutput bool) error {
+	infos, _, err := coresignatures.Inspect(files, level, sel)
+	if err != nil {
+		return err
+	}
+	info, ok := coresignatures.Find(infos, id)
+	if !ok {
+		return fmt.Errorf("signature %q not found", id)
+	}
+
+	if jsonOutput {
+		return writeJSON(os.Stdout, info)
+	}
+	writeSignature(os.Stdout, info)
+	return nil
+}
+
+func writeJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeSignatureList(out io.Writer, infos []coresignatures.SignatureInfo) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SIGNATURE ID\tKIND\tPART\tSEVERITY\tCONFIDENCE\tENTROPY\tSTATUS\tSOURCE") //nolint:errcheck
+	for _, v := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%g\t%s\t%s\n", v.ID, v.Kind, v.Part, v.Severity, v.ConfidenceLevel, v.Entropy, v.Status, v.Source) //nolint:errcheck
+	}
+	w.Flush() //nolint:errcheck
+}
+
+func writeSignature(out io.Writer, info coresignatures.SignatureInfo) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	rows := [][2]string{
+		{"ID", info.ID},
+		{"Description", info.Description},
+		{"Comment", info.Comment},
+		{"Kind", info.Kind},
+		{"Part", info.Part},
+		{"Key", info.Key},
+		{"Match", info.Match},
+		{"Severity", info.Severity},
+		{"Category", info.Category},
+		{"Tags", strings.Join(info.Tags, ", ")},
+		{"Confidence level", fmt.Sprintf("%d", info.ConfidenceLevel)},
+		{"Entropy", fmt.Sprintf("%g", info.Entropy)},
+		{"Entropy charset", info.EntropyCharset},
+		{"Min length", optionalInt(info.MinLength)},
+		{"Max length", optionalInt(info.MaxLength)},
+		{"Reject placeholders", optionalBool(info.RejectPlaceholders)},
+		{"Stopwords", strings.Join(info.Stopwords, ", ")},
+		{"Verifier", info.Verifier},
+		{"Status", info.Status},
+		{"Tests", fmt.Sprintf("%d", info.Tests)},
+		{"Source", info.Source},
+		{"Issues", strings.Join(info.Issues, "; ")},
+	}
+	for _, v := range rows {
+		if v[1] == "" {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\t%s\n", v[0], v[1]) //nolint:errcheck
+	}
+	w.Flush() //nolint:errcheck
+}
+
+// optionalInt and optionalBool return an empty string for unset values, so they are not printed
+func optionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+func optionalBool(v bool) string {
+	if !v {
+		return ""
+	}
+	return "yes"
+}
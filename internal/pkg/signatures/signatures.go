@@ -7,6 +7,7 @@ import (
 
 	"github.com/rumenvasilev/rvsecret/internal/config"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/github"
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
 	"github.com/rumenvasilev/rvsecret/internal/log"
 	"github.com/rumenvasilev/rvsecret/internal/session"
 	"github.com/rumenvasilev/rvsecret/internal/util"
@@ -117,7 +118,10 @@ func executeTests(dir string) bool {
 	}
 
 	// Run tests:
-	return isYaml(sigFiles)
+	if !isYaml(sigFiles) {
+		return false
+	}
+	return passesSignatureTests(sigFiles)
 }
 
 // cleanUp after ourselves and remove any temp garbage
@@ -127,6 +131,24 @@ func cleanUp(path string) {
 	}
 }
 
+// passesSignatureTests will run the examples embedded in every signature file
+func passesSignatureTests(files []string) bool {
+	log := log.Log
+	for _, v := range files {
+		report, err := coresignatures.TestFile(v)
+		if err != nil {
+			log.Error("Failed to test signatures file %q, error: %q", v, err.Error())
+			return false
+		}
+		if report.Failed() > 0 {
+			writeTestReport(os.Stdout, report)
+			log.Error("%d signature %s in file %q failed", report.Failed(), util.Pluralize(report.Failed(), "test", "tests"), v)
+			return false
+		}
+	}
+	return true
+}
+
 // runYamlTest would try to marshal the input file
 // if it can => true
 // if it cannot => false
@@ -0,0 +1,53 @@
+package signatures
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	coresignatures "github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/log"
+	"github.com/rumenvasilev/rvsecret/internal/util"
+)
+
+// Test will run the examples embedded in the signatures file and print a pass/fail table
+func Test(file string) error {
+	report, err := coresignatures.TestFile(file)
+	if err != nil {
+		return err
+	}
+
+	writeTestReport(os.Stdout, report)
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d out of %d signature %s failed", failed, len(report.Results), util.Pluralize(len(report.Results), "test", "tests"))
+	}
+	log.Log.Important("All %d signature %s passed.", len(report.Results), util.Pluralize(len(report.Results), "test", "tests"))
+	return nil
+}
+
+func writeTestReport(out io.Writer, report coresignatures.TestReport) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESULT\tSIGNATURE ID\tEXPECT\tEXAMPLE\tDESCRIPTION") //nolint:errcheck
+	for _, v := range report.Results {
+		result := "PASS"
+		if !v.Passed {
+			result = "FAIL"
+		}
+		expect := "nomatch"
+		if v.WantMatch {
+			expect = "match"
+		}
+		example := util.TruncateString(v.Example, 40)
+		if v.Err != nil {
+			expect = "-"
+			example = util.TruncateString(v.Err.Error(), 80)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result, v.SignatureID, expect, example, v.Description) //nolint:errcheck
+	}
+	w.Flush() //nolint:errcheck
+
+	if report.Untested > 0 {
+		log.Log.Warn("%d %s without any tests.", report.Untested, util.Pluralize(report.Untested, "signature", "signatures"))
+	}
+}
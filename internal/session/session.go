@@ -1,6 +1,7 @@
 package session
 
 import (
+	"fmt"
 	"runtime"
 	"sync"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/rumenvasilev/rvsecret/internal/core/finding"
 	providerapi "github.com/rumenvasilev/rvsecret/internal/core/provider/api"
 	"github.com/rumenvasilev/rvsecret/internal/core/signatures"
+	"github.com/rumenvasilev/rvsecret/internal/core/verify"
 	"github.com/rumenvasilev/rvsecret/internal/log"
 	scanapi "github.com/rumenvasilev/rvsecret/internal/pkg/scan/api"
 	"github.com/rumenvasilev/rvsecret/internal/stats"
@@ -30,6 +32,7 @@ type Session struct {
 	GithubUserRepos  []string
 	Organizations    []*github.Organization
 	Signatures       []signatures.Signature
+	Verifier         *verify.Runner `json:"-"` // Verifier checks the secrets found, nil unless verification is enabled
 }
 
 // NewSession is the entry point for starting a new scan session
@@ -55,12 +58,40 @@ func initialize(cfg *config.Config) (*Session, error) {
 	// init threads
 	s.initThreads()
 
-	var err error
-	s.Signatures, s.SignatureVersion, err = signatures.Load(cfg.Signatures.File, cfg.Global.ConfidenceLevel)
+	if cfg.Verify.Enabled {
+		s.Verifier = verify.NewRunner(cfg.Verify)
+	}
+
+	sel, err := selection(cfg)
+	if err != nil {
+		return s.start(), err
+	}
+	sources, err := signatures.Sources(cfg.Signatures.Path, cfg.Signatures.File)
+	if err == nil {
+		s.Signatures, s.SignatureVersion, err = signatures.Load(sources, cfg.Global.ConfidenceLevel, sel)
+	}
 
 	return s.start(), err
 }
 
+// selection will validate the signature filters and failure threshold from the configuration
+func selection(cfg *config.Config) (signatures.Selection, error) {
+	severity, err := signatures.ParseSeverity(cfg.Signatures.Severity)
+	if err != nil {
+		return signatures.Selection{}, fmt.Errorf("invalid signatures severity, %w", err)
+	}
+	cfg.Global.FailOnSeverity, err = signatures.ParseSeverity(cfg.Global.FailOnSeverity)
+	if err != nil {
+		return signatures.Selection{}, fmt.Errorf("invalid fail-on-severity, %w", err)
+	}
+	return signatures.Selection{
+		Severity: severity,
+		Enable:   cfg.Signatures.Enable,
+		Disable:  cfg.Signatures.Disable,
+		Tags:     cfg.Signatures.Tags,
+	}, nil
+}
+
 func (s *Session) withConfig(cfg *config.Config) *Session {
 	s.Config = cfg
 	return s
@@ -73,8 +104,12 @@ func (s *Session) start() *Session {
 }
 
 // Finish is called at the end of a scan session and used to generate discrete data points
-// for a given scan session including setting the status of a scan to finished.
+// for a given scan session including setting the status of a scan to finished. The findings which are
+// being verified are waited for.
 func (s *Session) Finish() {
+	if s.Verifier != nil {
+		s.Verifier.Wait()
+	}
 	s.State.Stats.FinishedAt = time.Now()
 	s.State.Stats.UpdateStatus(stats.StatusFinished)
 }
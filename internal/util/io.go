@@ -218,11 +218,21 @@ func IsBinaryFile(filePath string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	return IsBinary(buffer), nil
+}
+
+// IsBinary will identify binary content by its first bytes, like IsBinaryFile
+func IsBinary(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	buffer := make([]byte, 4)
+	copy(buffer, data)
 
 	// Check for common binary file magic numbers
 	for _, magic := range magicNumbers {
 		if bytesMatch(buffer, magic) {
-			return true, nil
+			return true
 		}
 	}
 
@@ -233,11 +243,11 @@ func IsBinaryFile(filePath string) (bool, error) {
 	runerr, p := utf8.DecodeRune(buffer)
 	if runerr == utf8.RuneError {
 		if p == 0 || p == 1 {
-			return true, nil
+			return true
 		}
 	}
 
-	return false, nil
+	return false
 }
 
 func bytesMatch(a, b []byte) bool {
@@ -15,6 +15,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rumenvasilev/rvsecret/assets"
 	"github.com/rumenvasilev/rvsecret/internal/config"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/azure"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/bitbucket"
+	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitea"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/github"
 	"github.com/rumenvasilev/rvsecret/internal/core/provider/gitlab"
 	"github.com/rumenvasilev/rvsecret/internal/log"
@@ -27,7 +30,6 @@ import (
 const (
 	GithubBaseURI   = github.RAWAddress
 	MaximumFileSize = 153600
-	GitLabBaseURL   = gitlab.Address
 	CspPolicy       = "default-src 'none'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; font-src 'self'"
 	ReferrerPolicy  = "no-referrer"
 	local           = "add/path/here"
@@ -93,7 +95,7 @@ func New(ctx context.Context, cfg config.Config, state *session.State) *Engine {
 	router.GET("/api/repositories", checkAuthN, func(c *gin.Context) {
 		c.JSON(200, state.Repositories)
 	})
-	router.GET("/api/files/:owner/:repo/:commit/*path", fetch{scanType: cfg.Global.ScanType}.file)
+	router.GET("/api/files/:owner/:repo/:commit/*path", fetch{scanType: cfg.Global.ScanType, azureURL: cfg.AzureDevOps.URL, gitlabURL: cfg.Gitlab.URL, bitbucketURL: cfg.Bitbucket.URL, giteaURL: cfg.Gitea.URL}.file)
 
 	return &Engine{
 		Listener: fmt.Sprintf("%s:%d", cfg.Global.BindAddress, cfg.Global.BindPort),
@@ -133,7 +135,11 @@ func (r rewrite) path(e *gin.Engine) gin.HandlerFunc {
 }
 
 type fetch struct {
-	scanType api.ScanType
+	scanType     api.ScanType
+	azureURL     string
+	gitlabURL    string
+	bitbucketURL string
+	giteaURL     string
 }
 
 // file returns a given path to a file that can be cicked on by a user
@@ -143,9 +149,22 @@ func (f fetch) file(c *gin.Context) {
 		fileURL := fmt.Sprintf("%s/%s/%s/%s%s", GithubBaseURI, c.Param("owner"), c.Param("repo"), c.Param("commit"), c.Param("path"))
 		getRemoteFile(c, fileURL)
 	case api.Gitlab:
-		results := util.CleanURLSpaces(c.Param("owner"), c.Param("repo"), c.Param("commit"), c.Param("path"))
-		fileURL := fmt.Sprintf("%s/%s/%s/%s/%s%s", GitLabBaseURL, results[0], results[1], "/-/raw/", results[2], results[3])
-		getRemoteFile(c, fileURL)
+		results := util.CleanURLSpaces(c.Param("owner"), c.Param("repo"), c.Param("commit"), strings.TrimPrefix(c.Param("path"), "/"))
+		getRemoteFile(c, gitlab.RawURL(f.gitlabURL, results[0], results[1], results[2], results[3]))
+	case api.Bitbucket:
+		getRemoteFile(c, bitbucket.RawURL(f.bitbucketURL, c.Param("owner"), c.Param("repo"), c.Param("commit"), strings.TrimPrefix(c.Param("path"), "/")))
+	case api.AzureDevOps:
+		// the owner is the organization and the project, which shifts the rest of the params by one
+		parts := strings.SplitN(strings.Join([]string{c.Param("owner"), c.Param("repo"), c.Param("commit")}, "/")+c.Param("path"), "/", 5)
+		if len(parts) < 5 {
+			c.JSON(http.StatusNotFound, gin.H{
+				"message": "No content",
+			})
+			return
+		}
+		getRemoteFile(c, azure.RawURL(f.azureURL, parts[0]+"/"+parts[1], parts[2], parts[3], parts[4]))
+	case api.Gitea:
+		getRemoteFile(c, gitea.RawURL(f.giteaURL, c.Param("owner"), c.Param("repo"), c.Param("commit"), strings.TrimPrefix(c.Param("path"), "/")))
 	default:
 		fileURL := fmt.Sprintf("%s/%s/%s/%s%s", local, c.Param("owner"), c.Param("repo"), c.Param("commit"), c.Param("path"))
 		getLocalFile(c, fileURL)